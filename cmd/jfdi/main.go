@@ -0,0 +1,136 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Command jfdi generates documents from a declarative template file.
+//
+// Usage:
+//
+//     jfdi [flags] TEMPLATE
+//
+// The template is a JSON or YAML file in the format described by
+// jfdi.ParseTemplate.  Flags:
+//
+//     --count N      number of documents to generate (default 1)
+//     --seed N       seed for the random number generator
+//     --format F     output format: ndjson, json or pretty (default ndjson)
+//     --out FILE     write to FILE instead of standard output
+//
+// The ndjson format writes one document per line; json writes a single JSON
+// array of documents; pretty writes indented documents separated by newlines.
+//
+// If --seed isn't given, a time-based seed is used and reported on standard
+// error, so that the output can be reproduced later.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/xdg-go/jfdi"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintf(os.Stderr, "jfdi: %v\n", err)
+		}
+		os.Exit(2)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) (err error) {
+	flags := flag.NewFlagSet("jfdi", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	count := flags.Int("count", 1, "number of documents to generate")
+	seed := flags.Int64("seed", 0, "seed for the random number generator (default time-based)")
+	format := flags.String("format", "ndjson", "output format: ndjson, json or pretty")
+	out := flags.String("out", "", "output file (default standard output)")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: jfdi [flags] TEMPLATE\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("exactly one template file is required")
+	}
+	if *count < 0 {
+		return errors.New("count must be non-negative")
+	}
+	switch *format {
+	case "ndjson", "json", "pretty":
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	g, err := jfdi.LoadTemplate(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var c *jfdi.Context
+	seeded := false
+	flags.Visit(func(f *flag.Flag) { seeded = seeded || f.Name == "seed" })
+	if seeded {
+		c = jfdi.NewContextWithSeed(*seed)
+	} else {
+		c = jfdi.NewContext()
+		fmt.Fprintf(stderr, "jfdi: seed %d\n", c.Seed)
+	}
+
+	w := stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}()
+		w = f
+	}
+	return write(w, g, c, *count, *format)
+}
+
+// write generates count documents and writes them to w in the given format.
+func write(w io.Writer, g jfdi.Generator, c *jfdi.Context, count int, format string) error {
+	if format != "json" {
+		enc := jfdi.NewEncoder(w)
+		if format == "pretty" {
+			enc.SetIndent("  ")
+		}
+		return enc.Generate(c, g, count)
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("[")
+	for i := 0; i < count; i++ {
+		doc, err := jfdi.Generate(c, g)
+		if err != nil {
+			return fmt.Errorf("document %d: %v", i, err)
+		}
+		buf, err := json.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("document %d: %v", i, err)
+		}
+		if i > 0 {
+			bw.WriteString(",")
+		}
+		bw.Write(buf)
+	}
+	bw.WriteString("]\n")
+	return bw.Flush()
+}
@@ -0,0 +1,173 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testTemplate = `
+id: {$int: [1, 1000000]}
+name: {$pick: [Alice, Bob, Carol]}
+`
+
+func writeTemplate(t *testing.T) (string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "jfdi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(dir, "model.yaml")
+	if err := ioutil.WriteFile(filename, []byte(testTemplate), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return filename, func() { os.RemoveAll(dir) }
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	filename, cleanup := writeTemplate(t)
+	defer cleanup()
+
+	cases := []struct {
+		format string
+		check  func(string) error
+	}{
+		{"ndjson", func(s string) error {
+			lines := strings.Split(strings.TrimSpace(s), "\n")
+			if len(lines) != 3 {
+				t.Errorf("expected 3 lines, got %d", len(lines))
+			}
+			for _, l := range lines {
+				var doc map[string]interface{}
+				if err := json.Unmarshal([]byte(l), &doc); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		{"json", func(s string) error {
+			var docs []map[string]interface{}
+			if err := json.Unmarshal([]byte(s), &docs); err != nil {
+				return err
+			}
+			if len(docs) != 3 {
+				t.Errorf("expected 3 documents, got %d", len(docs))
+			}
+			return nil
+		}},
+		{"pretty", func(s string) error {
+			dec := json.NewDecoder(strings.NewReader(s))
+			for i := 0; i < 3; i++ {
+				var doc map[string]interface{}
+				if err := dec.Decode(&doc); err != nil {
+					return err
+				}
+			}
+			if !strings.Contains(s, "\n  \"id\"") {
+				t.Errorf("output not indented: %s", s)
+			}
+			return nil
+		}},
+	}
+
+	for _, c := range cases {
+		var stdout, stderr bytes.Buffer
+		err := run([]string{"--count", "3", "--seed", "42", "--format", c.format, filename}, &stdout, &stderr)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.format, err)
+			continue
+		}
+		if err := c.check(stdout.String()); err != nil {
+			t.Errorf("%s: invalid output: %v\n%s", c.format, err, stdout.String())
+		}
+		if stderr.Len() != 0 {
+			t.Errorf("%s: unexpected stderr: %s", c.format, stderr.String())
+		}
+	}
+}
+
+func TestRun_Seed(t *testing.T) {
+	t.Parallel()
+
+	filename, cleanup := writeTemplate(t)
+	defer cleanup()
+
+	// The same seed gives the same output.
+	var out1, out2, stderr bytes.Buffer
+	if err := run([]string{"--count=5", "--seed=7", filename}, &out1, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if err := run([]string{"--count=5", "--seed=7", filename}, &out2, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if out1.String() != out2.String() {
+		t.Errorf("seeded output differs:\n%s\n%s", out1.String(), out2.String())
+	}
+
+	// Without a seed, the seed used is reported.
+	var out3 bytes.Buffer
+	stderr.Reset()
+	if err := run([]string{filename}, &out3, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stderr.String(), "jfdi: seed ") {
+		t.Errorf("seed not reported: %q", stderr.String())
+	}
+}
+
+func TestRun_Out(t *testing.T) {
+	t.Parallel()
+
+	filename, cleanup := writeTemplate(t)
+	defer cleanup()
+
+	out := filepath.Join(filepath.Dir(filename), "out.ndjson")
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"--count=2", "--seed=1", "--out", out, filename}, &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(buf), "\n"); n != 2 {
+		t.Errorf("expected 2 documents in file, got %d", n)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("unexpected stdout: %s", stdout.String())
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	t.Parallel()
+
+	filename, cleanup := writeTemplate(t)
+	defer cleanup()
+
+	cases := [][]string{
+		{},
+		{filename, filename},
+		{"--count=-1", filename},
+		{"--format=xml", filename},
+		{"--bogus", filename},
+		{filepath.Join(filepath.Dir(filename), "missing.yaml")},
+	}
+	for _, args := range cases {
+		var stdout, stderr bytes.Buffer
+		if err := run(args, &stdout, &stderr); err == nil {
+			t.Errorf("expected error for args %v", args)
+		}
+	}
+}
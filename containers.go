@@ -22,7 +22,30 @@ func Object(xs ...interface{}) Generator {
 // a maximum depth in a compound data structure.  The resulting Generator will
 // return nil instead of a Map if the maxDepth is exceeded.  A maxDepth of 0
 // means depth is unlimited.
+//
+// Because depth is restored when each container finishes, siblings are
+// generated at the same depth and MaxDepthObject can bound recursive models.
 func MaxDepthObject(maxDepth int, xs ...interface{}) Generator {
+	return object(maxDepth, false, xs)
+}
+
+// KeyedObject works like Object, but generates the value for each key with
+// its own random stream, derived from the key name and a single random draw
+// from the Context.  Adding or removing a key therefore leaves the values of
+// the other keys unchanged for a given seed, which keeps golden fixtures
+// stable as a template evolves.  Nested Objects should also be KeyedObjects
+// to get the same stability at every level.
+func KeyedObject(xs ...interface{}) Generator {
+	return MaxDepthKeyedObject(0, xs...)
+}
+
+// MaxDepthKeyedObject works like MaxDepthObject, but with per-key random
+// streams like KeyedObject.
+func MaxDepthKeyedObject(maxDepth int, xs ...interface{}) Generator {
+	return object(maxDepth, true, xs)
+}
+
+func object(maxDepth int, keyed bool, xs []interface{}) Generator {
 	// Generator constructs an empty Map by iterating over keys of input map.
 	// Each key corresponds to either a value or a Generator.  If its a
 	// Generator, get the output value from it.
@@ -31,6 +54,9 @@ func MaxDepthObject(maxDepth int, xs ...interface{}) Generator {
 			c = NewContext()
 		}
 		c.Depth++
+		omit := c.omit
+		c.omit = nil
+		defer func() { c.Depth--; c.omit = omit }()
 		if maxDepth > 0 && c.Depth > maxDepth {
 			return nil
 		}
@@ -38,11 +64,11 @@ func MaxDepthObject(maxDepth int, xs ...interface{}) Generator {
 		// Build up model from inputs. Inputs must be maps or Generators of maps.
 		model := Map{}
 		for _, x := range xs {
-			if m, ok := toMap(c, x); ok {
-				mergeMaps(model, m)
-			} else {
-				panic("arguments must be Maps or generators of Maps")
+			m, got, ok := toMap(c, x)
+			if !ok {
+				c.fail("arguments must be Maps or generators of Maps", "Map", got)
 			}
+			mergeMaps(model, m)
 		}
 
 		// Call expand by key in sorted order to ensure determinism
@@ -53,8 +79,26 @@ func MaxDepthObject(maxDepth int, xs ...interface{}) Generator {
 			keys = append(keys, k)
 		}
 		sort.Strings(keys)
+		var nonce int64
+		if keyed {
+			nonce = c.Rand.Int63()
+		}
 		for _, k := range keys {
-			output[k] = expand(c, model[k])
+			c.pushKey(k)
+			kc := c
+			if keyed {
+				kc = c.fork(deriveSeed(nonce, hashString(k)))
+			}
+			// Optional sets omitted to leave out the key.  A generator
+			// that wraps Optional and replaces its nil keeps the key.
+			omitted := false
+			kc.omit = &omitted
+			v := expand(kc, model[k])
+			kc.omit = nil
+			if !omitted || v != nil {
+				output[k] = v
+			}
+			c.pop()
 		}
 		return output
 	}
@@ -83,17 +127,23 @@ func MaxDepthArray(maxDepth int, length, elementModel interface{}) Generator {
 			c = NewContext()
 		}
 		c.Depth++
+		omit := c.omit
+		c.omit = nil
+		defer func() { c.Depth--; c.omit = omit }()
 		if maxDepth > 0 && c.Depth > maxDepth {
 			return nil
 		}
 
-		n, ok := toInt(c, length)
+		n, got, ok := toInt(c, length)
 		if !ok || n < 0 {
-			panic("length must be a non-negative int or generate a non-negative int")
+			c.fail("length must be a non-negative int or generate a non-negative int", "non-negative int", got)
+			n = 0
 		}
 		output := make(Slice, n)
 		for i := 0; i < n; i++ {
+			c.pushIndex(i)
 			output[i] = expand(c, elementModel)
+			c.pop()
 		}
 		return output
 	}
@@ -110,11 +160,16 @@ func Sequence(elementModels ...interface{}) Generator {
 		if c == nil {
 			c = NewContext()
 		}
-
 		c.Depth++
+		omit := c.omit
+		c.omit = nil
+		defer func() { c.Depth--; c.omit = omit }()
+
 		output := make(Slice, len(elementModels))
 		for i := 0; i < len(elementModels); i++ {
+			c.pushIndex(i)
 			output[i] = expand(c, elementModels[i])
+			c.pop()
 		}
 		return output
 	}
@@ -119,3 +119,56 @@ func TestSeededMapMerge(t *testing.T) {
 		checkStringIs(t, first[i], second[i], fmt.Sprintf("seeded map merge, doc %d", i))
 	}
 }
+
+func TestScopedDepth(t *testing.T) {
+	t.Parallel()
+
+	// Sibling containers are generated at the same depth, so a later sibling
+	// isn't cut off by an earlier one.
+	f := Object(Map{
+		"a": MaxDepthArray(2, 1, 1),
+		"b": MaxDepthArray(2, 1, 2),
+		"c": MaxDepthArray(2, 1, 3),
+		"d": Sequence(MaxDepthObject(3, Map{"x": 4}), MaxDepthObject(3, Map{"y": 5})),
+	})
+	checkStringIs(t, f(nil).(Map).String(),
+		`{"a":[1],"b":[2],"c":[3],"d":[{"x":4},{"y":5}]}`, "sibling depth")
+
+	// Depth is restored after generation, including when the limit is hit.
+	c := NewContext()
+	MaxDepthObject(1, Map{"x": MaxDepthObject(1, Map{})})(c)
+	if c.Depth != 0 {
+		t.Errorf("depth not restored: got %d; wanted 0", c.Depth)
+	}
+}
+
+func TestKeyedObject(t *testing.T) {
+	t.Parallel()
+
+	before := KeyedObject(Map{
+		"a": Int(1, 1000000),
+		"c": Words(3),
+		"d": KeyedObject(Map{"x": Int(1, 1000000)}),
+	})
+	after := KeyedObject(Map{
+		"a": Int(1, 1000000),
+		"b": Array(10, Int(1, 1000000)),
+		"c": Words(3),
+		"d": KeyedObject(Map{"w": Float64(0, 1), "x": Int(1, 1000000)}),
+	})
+
+	// Adding keys doesn't change the values of other keys.
+	c1 := NewContextWithSeed(42)
+	c2 := NewContextWithSeed(42)
+	for i := 0; i < 10; i++ {
+		x := before(c1).(Map)
+		y := after(c2).(Map)
+		delete(y, "b")
+		delete(y["d"].(Map), "w")
+		checkStringIs(t, x.String(), y.String(), fmt.Sprintf("keyed object %d", i))
+	}
+
+	// Paths are tracked as with Object.
+	f := KeyedObject(Map{"a": Array(1, func(c *Context) interface{} { return c.Path() })})
+	checkStringIs(t, f(nil).(Map).String(), `{"a":["/a/0"]}`, "keyed object path")
+}
@@ -9,7 +9,10 @@ package jfdi
 import (
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"math/rand"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -57,17 +60,169 @@ func (s Slice) String() string {
 // The Context type is passed down through recursive Generator
 // calls.  It tracks depth, provides an independent PRNG, and
 // supports user-defined key/value data.
+//
+// Depth is the nesting level of the container currently being generated.  It
+// is zero at the top level; Object, Array and Sequence increment it while
+// generating their contents and decrement it when they finish.
+//
+// The Context also tracks the path to the value being generated; see Path.
+//
+// Seed records the value used to seed Rand when the Context was constructed.
+// Generators draw all randomness from Rand, so a Context built with
+// NewContextWithSeed and the same seed reproduces the same output.
 type Context struct {
 	Depth int
 	Rand  *rand.Rand
+	Seed  int64
 	Value Map
+
+	path   []string
+	refs   []string
+	probe  *probe
+	omit   *bool
+	shared *sharedState
+}
+
+// sharedState holds generator state that belongs to a Context and is shared
+// with Contexts forked from it.
+type sharedState struct {
+	pools    map[*Pool]*poolState
+	unique   map[interface{}]map[interface{}]bool
+	counters map[*counterKey]int
 }
 
-// NewContext initializes a Context with a fresh PRNG and value map.
+// state returns the Context's shared state, creating it if needed.
+func (c *Context) state() *sharedState {
+	if c.shared == nil {
+		c.shared = &sharedState{}
+	}
+	return c.shared
+}
+
+// NewContext initializes a Context with a fresh PRNG and value map.  The PRNG
+// is seeded from the current time; the seed is recorded in the Context's Seed
+// field so that a run can be reproduced later with NewContextWithSeed.
 func NewContext() *Context {
+	return NewContextWithSeed(time.Now().UnixNano())
+}
+
+// NewContextWithSeed initializes a Context with a PRNG seeded with the given
+// seed and a fresh value map.  Generators called with Contexts constructed
+// from the same seed produce identical output.
+//
+// For example, to make a test failure replayable, log the seed when the test
+// fails and then hard-code it while debugging:
+//
+//     c := jfdi.NewContext()
+//     defer func() {
+//         if t.Failed() {
+//             t.Logf("jfdi seed: %d", c.Seed)
+//         }
+//     }()
+func NewContextWithSeed(seed int64) *Context {
 	return &Context{
-		Rand:  rand.New(rand.NewSource(time.Now().UnixNano())),
-		Value: make(Map),
+		Rand:   rand.New(rand.NewSource(seed)),
+		Seed:   seed,
+		Value:  make(Map),
+		shared: &sharedState{},
+	}
+}
+
+// deriveSeed combines a seed with a key to produce a new seed, using the
+// SplitMix64 finalizer so that nearby keys give unrelated seeds.
+func deriveSeed(seed int64, key uint64) int64 {
+	z := uint64(seed) + (key+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// Fork returns a child Context with an independent PRNG whose seed is derived
+// from this Context's Seed and the given name.  Forking the same name always
+// gives the same random stream, and a forked stream is unaffected by how much
+// randomness the parent or other forks consume, so separate parts of a model
+// (e.g. c.Fork("users") and c.Fork("orders")) can change independently
+// without disturbing each other's values.
+//
+// The child starts at the parent's depth and path and shares its Value map
+// and its recorded state, such as Pools.
+func (c *Context) Fork(name string) *Context {
+	return c.fork(deriveSeed(c.Seed, hashString(name)))
+}
+
+func (c *Context) fork(seed int64) *Context {
+	child := *c
+	child.Rand = rand.New(rand.NewSource(seed))
+	child.Seed = seed
+	return &child
+}
+
+// hashString returns the 64-bit FNV-1a hash of a string.
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// Path returns a JSON Pointer (RFC 6901) to the value currently being
+// generated, relative to the outermost container generated with this Context,
+// e.g. "/users/3/address/zip".  Object adds each key to the path while
+// generating its value; Array and Sequence add each element index.  At the
+// top level, the path is the empty string.
+func (c *Context) Path() string {
+	var sb strings.Builder
+	for _, p := range c.path {
+		sb.WriteByte('/')
+		sb.WriteString(pointerEscaper.Replace(p))
+	}
+	return sb.String()
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func (c *Context) pushKey(k string) {
+	c.path = append(c.path, k)
+}
+
+func (c *Context) pushIndex(i int) {
+	c.path = append(c.path, strconv.Itoa(i))
+}
+
+func (c *Context) pop() {
+	c.path = c.path[:len(c.path)-1]
+}
+
+// fail reports a *GenerateError for the current path.  The expected
+// argument describes the required type and got is the offending value.
+func (c *Context) fail(msg, expected string, got interface{}) {
+	c.report(&GenerateError{
+		Path:     c.Path(),
+		Msg:      msg,
+		Expected: expected,
+		Actual:   describe(got),
+	})
+}
+
+// report panics with an error, unless the Context is a probe used by
+// Validate, in which case the error is recorded and report returns so that
+// the caller can continue with a fallback value.
+func (c *Context) report(e *GenerateError) {
+	if c.probe == nil {
+		panic(e)
+	}
+	c.probe.errs = append(c.probe.errs, e)
+}
+
+// describe returns a short description of a value's type for error messages.
+// Scalars include the value itself.
+func describe(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "nil"
+	case bool, int, int32, int64, float64, string:
+		return fmt.Sprintf("%T %#v", v, v)
+	default:
+		return fmt.Sprintf("%T", v)
 	}
 }
 
@@ -78,48 +233,55 @@ func toGenerator(in interface{}) (Generator, bool) {
 		return f, true
 	} else if f, ok := in.(func() interface{}); ok {
 		return func(*Context) interface{} { return f() }, true
+	} else if f, ok := in.(ErrorGenerator); ok {
+		return f.mustGenerator(), true
+	} else if f, ok := in.(func(c *Context) (interface{}, error)); ok {
+		return ErrorGenerator(f).mustGenerator(), true
 	} else {
 		return zeroGenerator, false
 	}
 }
 
-func toMap(c *Context, in interface{}) (Map, bool) {
+func toMap(c *Context, in interface{}) (Map, interface{}, bool) {
 	if x, ok := in.(Map); ok {
-		return x, true
+		return x, x, true
 	}
 	if f, ok := toGenerator(in); ok {
 		v := f(c)
 		if x, ok := v.(Map); ok {
-			return x, true
+			return x, v, true
 		}
+		return Map{}, v, false
 	}
-	return Map{}, false
+	return Map{}, in, false
 }
 
-func toInt(c *Context, in interface{}) (int, bool) {
+func toInt(c *Context, in interface{}) (int, interface{}, bool) {
 	if x, ok := in.(int); ok {
-		return x, true
+		return x, x, true
 	}
 	if f, ok := toGenerator(in); ok {
 		v := f(c)
 		if x, ok := v.(int); ok {
-			return x, true
+			return x, v, true
 		}
+		return 0, v, false
 	}
-	return 0, false
+	return 0, in, false
 }
 
-func toStr(c *Context, in interface{}) (string, bool) {
+func toStr(c *Context, in interface{}) (string, interface{}, bool) {
 	if x, ok := in.(string); ok {
-		return x, true
+		return x, x, true
 	}
 	if f, ok := toGenerator(in); ok {
 		v := f(c)
 		if x, ok := v.(string); ok {
-			return x, true
+			return x, v, true
 		}
+		return "", v, false
 	}
-	return "", false
+	return "", in, false
 }
 
 func expand(c *Context, in interface{}) interface{} {
@@ -120,3 +120,93 @@ func TestSlice_String(t *testing.T) {
 		t.Errorf("Didn't get expected marshaling error: got %q", s)
 	}
 }
+
+func TestNewContextWithSeed(t *testing.T) {
+	t.Parallel()
+
+	f := Object(Map{
+		"a": Int(1, 1000),
+		"b": Array(Int(1, 5), Pick("x", "y", Float64(0, 1))),
+		"c": Digits("###-##-####"),
+	})
+
+	c1 := NewContextWithSeed(42)
+	c2 := NewContextWithSeed(42)
+	if c1.Seed != 42 {
+		t.Errorf("seed not recorded: got %d; wanted 42", c1.Seed)
+	}
+
+	for i := 0; i < 10; i++ {
+		checkStringIs(t, f(c1).(Map).String(), f(c2).(Map).String(), "seeded context")
+	}
+
+	// A time-seeded Context can be replayed from its recorded seed.
+	c3 := NewContext()
+	c4 := NewContextWithSeed(c3.Seed)
+	checkStringIs(t, f(c3).(Map).String(), f(c4).(Map).String(), "replayed context")
+}
+
+func TestContext_Path(t *testing.T) {
+	t.Parallel()
+
+	path := func(c *Context) interface{} { return c.Path() }
+
+	f := Object(Map{
+		"top": path,
+		"users": Array(2, Object(Map{
+			"address": Object(Map{"zip": path}),
+		})),
+		"a/b~c": Sequence(path, path),
+	})
+	checkStringIs(t, f(nil).(Map).String(),
+		`{"a/b~c":["/a~1b~0c/0","/a~1b~0c/1"],"top":"/top",`+
+			`"users":[{"address":{"zip":"/users/0/address/zip"}},{"address":{"zip":"/users/1/address/zip"}}]}`,
+		"paths")
+
+	c := NewContext()
+	f(c)
+	checkStringIs(t, c.Path(), "", "path restored")
+}
+
+func TestContext_PathInPanic(t *testing.T) {
+	t.Parallel()
+
+	f := Object(Map{
+		"users": Array(1, Object(Map{
+			"tags": Array("three", 42),
+		})),
+	})
+
+	defer func() {
+		r := recover()
+		e, _ := r.(error)
+		if e == nil || !strings.Contains(e.Error(), `"/users/0/tags"`) {
+			t.Errorf("panic message doesn't include path: got %v", r)
+		}
+	}()
+	f(nil)
+}
+
+func TestContext_Fork(t *testing.T) {
+	t.Parallel()
+
+	f := Array(5, Int(1, 1000000))
+
+	// Forks with the same name produce the same stream regardless of how
+	// much randomness the parent consumed.
+	c1 := NewContextWithSeed(42)
+	c2 := NewContextWithSeed(42)
+	f(c2)
+	checkStringIs(t, f(c1.Fork("users")).(Slice).String(), f(c2.Fork("users")).(Slice).String(), "fork")
+
+	// Forks with different names produce different streams.
+	if f(c1.Fork("users")).(Slice).String() == f(c1.Fork("orders")).(Slice).String() {
+		t.Errorf("forks with different names produced the same stream")
+	}
+
+	// Forks share the parent's Value map.
+	c1.Fork("x").Value["y"] = 1
+	if c1.Value["y"] != 1 {
+		t.Errorf("fork doesn't share Value map")
+	}
+}
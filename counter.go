@@ -0,0 +1,65 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A counterKey identifies the state of a single Counter generator.
+type counterKey struct {
+	_ byte
+}
+
+// Counter returns a generator of incrementing int values: `start` the first
+// time it's called with a Context, then start+step, start+2*step and so on.
+// This is useful for IDs like primary keys.  The count is stored in the
+// Context, so separate Contexts count independently and Contexts forked from a
+// Context share its count.
+//
+// Parallel generates each document with its own Context, so every document
+// counts from `start`.  For IDs that are unique across a Parallel run, derive
+// them from the document index passed to emit instead.
+func Counter(start, step int) Generator {
+	key := &counterKey{}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		s := c.state()
+		if s.counters == nil {
+			s.counters = make(map[*counterKey]int)
+		}
+		n, ok := s.counters[key]
+		if !ok {
+			n = start
+		}
+		s.counters[key] = n + step
+		return n
+	}
+}
+
+// Serial works like Counter, but formats each count as a string with a
+// fmt.Sprintf format that takes a single integer argument.  For example, to
+// generate invoice numbers "INV-000001", "INV-000002" and so on:
+//
+//     jfdi.Serial("INV-%06d", 1, 1)
+//
+// If the format doesn't format an integer, it panics.
+func Serial(format string, start, step int) Generator {
+	if s := fmt.Sprintf(format, start); strings.Contains(s, "%!") {
+		panic(fmt.Sprintf("invalid serial format %q: %s", format, s))
+	}
+	counter := Counter(start, step)
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		return fmt.Sprintf(format, counter(c))
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"testing"
+)
+
+func TestCounter(t *testing.T) {
+	t.Parallel()
+
+	g := Array(4, Counter(10, 5))
+	c := NewContext()
+	checkStringIs(t, g(c).(Slice).String(), `[10,15,20,25]`, "Counter")
+	checkStringIs(t, g(c).(Slice).String(), `[30,35,40,45]`, "Counter continues")
+	checkStringIs(t, g(c.Fork("x")).(Slice).String(), `[50,55,60,65]`, "forked Context shares count")
+	checkStringIs(t, g(NewContext()).(Slice).String(), `[10,15,20,25]`, "new Context starts over")
+
+	// Separate generators count separately.
+	checkStringIs(t, Array(3, Counter(3, -1))(c).(Slice).String(), `[3,2,1]`, "Counter down")
+}
+
+func TestSerial(t *testing.T) {
+	t.Parallel()
+
+	g := Object(Map{"id": Counter(1, 1), "invoice": Serial("INV-%06d", 1, 1)})
+	c := NewContextWithSeed(42)
+	checkStringIs(t, g(c).(Map).String(), `{"id":1,"invoice":"INV-000001"}`, "Serial")
+	checkStringIs(t, g(c).(Map).String(), `{"id":2,"invoice":"INV-000002"}`, "Serial")
+
+	for _, format := range []string{"INV-%s", "INV", "%d-%d"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for format %q", format)
+				}
+			}()
+			Serial(format, 1, 1)
+		}()
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Normal returns a generator of float64 values from a normal distribution
+// with the given mean and standard deviation.  If `stddev` is negative, it
+// panics.
+func Normal(mean, stddev float64) Generator {
+	if stddev < 0 {
+		panic("standard deviation must be non-negative")
+	}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		return mean + stddev*c.Rand.NormFloat64()
+	}
+}
+
+// LogNormal returns a generator of float64 values whose logarithm is normally
+// distributed with mean `mu` and standard deviation `sigma`.  This is a good
+// model for skewed positive quantities like order totals.  If `sigma` is
+// negative, it panics.
+func LogNormal(mu, sigma float64) Generator {
+	if sigma < 0 {
+		panic("sigma must be non-negative")
+	}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		return math.Exp(mu + sigma*c.Rand.NormFloat64())
+	}
+}
+
+// Exponential returns a generator of float64 values from an exponential
+// distribution with the given rate (the mean is 1/rate).  This is a good model
+// for times between independent events.  If `rate` is not positive, it
+// panics.
+func Exponential(rate float64) Generator {
+	if !(rate > 0) {
+		panic("rate must be positive")
+	}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		return c.Rand.ExpFloat64() / rate
+	}
+}
+
+// Poisson returns a generator of non-negative int values from a Poisson
+// distribution with mean `lambda`, such as the number of events in a fixed
+// interval.  The output may be used as an Array length.  If `lambda` is
+// negative, it panics.
+func Poisson(lambda float64) Generator {
+	if !(lambda >= 0) || math.IsInf(lambda, 0) {
+		panic("lambda must be finite and non-negative")
+	}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		return poisson(c.Rand, lambda)
+	}
+}
+
+// poisson samples from a Poisson distribution using Knuth's multiplication
+// method for small means and Hörmann's transformed rejection method (PTRS)
+// for larger ones.
+func poisson(r *rand.Rand, lambda float64) int {
+	if lambda < 10 {
+		limit := math.Exp(-lambda)
+		n, p := 0, r.Float64()
+		for p > limit {
+			n++
+			p *= r.Float64()
+		}
+		return n
+	}
+
+	slam := math.Sqrt(lambda)
+	loglam := math.Log(lambda)
+	b := 0.931 + 2.53*slam
+	a := -0.059 + 0.02483*b
+	invalpha := 1.1239 + 1.1328/(b-3.4)
+	vr := 0.9277 - 3.6224/(b-2)
+	for {
+		u := r.Float64() - 0.5
+		v := r.Float64()
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + lambda + 0.43)
+		if us >= 0.07 && v <= vr {
+			return int(k)
+		}
+		if k < 0 || (us < 0.013 && v > us) {
+			continue
+		}
+		lg, _ := math.Lgamma(k + 1)
+		if math.Log(v)+math.Log(invalpha)-math.Log(a/(us*us)+b) <= -lambda+k*loglam-lg {
+			return int(k)
+		}
+	}
+}
+
+// Zipf returns a generator of int values in the range [0, imax] from a Zipf
+// distribution, where the probability of k is proportional to (v + k) ** -s.
+// This is a good model for popularity, like page views or product sales.  It
+// panics unless `s` > 1 and `v` >= 1.
+func Zipf(s, v float64, imax uint64) Generator {
+	if !(s > 1) || !(v >= 1) {
+		panic("Zipf requires s > 1 and v >= 1")
+	}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		return int(rand.NewZipf(c.Rand, s, v, imax).Uint64())
+	}
+}
+
+// Beta returns a generator of float64 values in the range [0,1] from a beta
+// distribution with shape parameters `alpha` and `beta`.  This is a good model
+// for proportions and rates.  If either parameter is not positive, it panics.
+func Beta(alpha, beta float64) Generator {
+	if !(alpha > 0) || !(beta > 0) {
+		panic("alpha and beta must be positive")
+	}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		x := gamma(c.Rand, alpha)
+		y := gamma(c.Rand, beta)
+		return x / (x + y)
+	}
+}
+
+// gamma samples from a gamma distribution with the given shape and unit
+// scale, using the method of Marsaglia and Tsang.
+func gamma(r *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		// Boost the shape and correct with a uniform power.
+		return gamma(r, shape+1) * math.Pow(r.Float64(), 1/shape)
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := r.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := r.Float64()
+		if u < 1-0.0331*x*x*x*x || math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
+
+// Triangular returns a generator of float64 values in the range [low,high]
+// from a triangular distribution peaking at `mode`.  It is a simple model when
+// only the minimum, most likely and maximum values are known.  It panics
+// unless low <= mode <= high.
+func Triangular(low, mode, high float64) Generator {
+	if !(low <= mode && mode <= high) {
+		panic("arguments must satisfy low <= mode <= high")
+	}
+	if low == high {
+		return func(c *Context) interface{} {
+			return low
+		}
+	}
+	split := (mode - low) / (high - low)
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		u := c.Rand.Float64()
+		if u < split {
+			return low + math.Sqrt(u*(high-low)*(mode-low))
+		}
+		return high - math.Sqrt((1-u)*(high-low)*(high-mode))
+	}
+}
+
+// Clamp returns a generator that limits the numbers produced by a model to the
+// range [low,high].  The model must be a number or produce one; int and
+// float64 values are clamped and returned with the same type.  For example, to
+// generate order totals that are usually around 50 but never over 1000:
+//
+//     jfdi.Clamp(0, 1000, jfdi.LogNormal(4, 1))
+//
+// Either bound may be infinite, such as for Clamp(0, math.Inf(1), model).  If
+// no int lies in the range, such as for Clamp(0.5, 0.7, model), int values are
+// clamped as float64 values instead.  If `low` is greater than `high` or
+// either is NaN, it panics.
+func Clamp(low, high float64, model interface{}) Generator {
+	if math.IsNaN(low) || math.IsNaN(high) {
+		panic("arguments must not be NaN")
+	}
+	if low > high {
+		panic("first argument must be <= second argument")
+	}
+	// Saturate the int bounds to the int range.
+	flow, fhigh := math.Ceil(low), math.Floor(high)
+	hasInt := flow <= fhigh && flow < intRange && fhigh >= -intRange
+	ilow, ihigh := -maxInt-1, maxInt
+	if flow > -intRange {
+		ilow = int(flow)
+	}
+	if fhigh < intRange {
+		ihigh = int(fhigh)
+	}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		switch x := expand(c, model).(type) {
+		case int:
+			if !hasInt {
+				return math.Max(low, math.Min(high, float64(x)))
+			}
+			if x < ilow {
+				return ilow
+			}
+			if x > ihigh {
+				return ihigh
+			}
+			return x
+		case float64:
+			return math.Max(low, math.Min(high, x))
+		default:
+			c.fail("model must be or generate a number", "int or float64", x)
+			return x
+		}
+	}
+}
+
+// Round returns a generator that rounds the float64 values produced by a model
+// to the nearest int, so continuous distributions can be used where an int is
+// required, such as an Array length.  Int values are returned unchanged.
+//
+//     jfdi.Array(jfdi.Round(jfdi.Clamp(0, 20, jfdi.Normal(5, 2))), jfdi.Word())
+func Round(model interface{}) Generator {
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		switch x := expand(c, model).(type) {
+		case int:
+			return x
+		case float64:
+			return int(math.Round(x))
+		default:
+			c.fail("model must be or generate a number", "int or float64", x)
+			return 0
+		}
+	}
+}
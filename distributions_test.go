@@ -0,0 +1,158 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"math"
+	"testing"
+)
+
+// sampleMean draws n float64 or int values from a generator with a seeded
+// Context and returns their mean, checking each against a range.
+func sampleMean(t *testing.T, g Generator, n int, low, high float64) float64 {
+	t.Helper()
+	c := NewContextWithSeed(42)
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		var x float64
+		switch v := g(c).(type) {
+		case int:
+			x = float64(v)
+		case float64:
+			x = v
+		default:
+			t.Fatalf("unexpected type %T", v)
+		}
+		if x < low || x > high {
+			t.Fatalf("value %v not in range [%v, %v]", x, low, high)
+		}
+		sum += x
+	}
+	return sum / float64(n)
+}
+
+func checkMean(t *testing.T, label string, got, want, tolerance float64) {
+	t.Helper()
+	if math.Abs(got-want) > tolerance {
+		t.Errorf("%s: mean %v; wanted %v ± %v", label, got, want, tolerance)
+	}
+}
+
+func TestDistributions(t *testing.T) {
+	t.Parallel()
+
+	const n = 20000
+	inf := math.Inf(1)
+
+	cases := []struct {
+		label     string
+		gen       Generator
+		low, high float64
+		mean, tol float64
+	}{
+		{"Normal", Normal(10, 2), -inf, inf, 10, 0.1},
+		{"LogNormal", LogNormal(1, 0.5), 0, inf, math.Exp(1 + 0.125), 0.1},
+		{"Exponential", Exponential(4), 0, inf, 0.25, 0.01},
+		{"Poisson small", Poisson(3.5), 0, inf, 3.5, 0.1},
+		{"Poisson large", Poisson(100), 0, inf, 100, 0.5},
+		{"Poisson zero", Poisson(0), 0, 0, 0, 0},
+		{"Zipf", Zipf(2, 1, 10), 0, 10, -1, -1},
+		{"Beta", Beta(2, 6), 0, 1, 0.25, 0.01},
+		{"Beta small", Beta(0.5, 0.5), 0, 1, 0.5, 0.02},
+		{"Triangular", Triangular(0, 3, 9), 0, 9, 4, 0.1},
+		{"Triangular point", Triangular(2, 2, 2), 2, 2, 2, 0},
+		{"Clamp float", Clamp(5, 15, Normal(10, 10)), 5, 15, 10, 0.2},
+		{"Clamp int", Clamp(2, 4, Int(0, 6)), 2, 4, 3, 0.1},
+		{"Round", Round(Triangular(0, 0, 10)), 0, 10, 10.0 / 3, 0.1},
+	}
+
+	for _, x := range cases {
+		got := sampleMean(t, x.gen, n, x.low, x.high)
+		if x.tol >= 0 {
+			checkMean(t, x.label, got, x.mean, x.tol)
+		}
+	}
+
+	// Zipf favors small values.
+	counts := make([]int, 11)
+	c := NewContextWithSeed(42)
+	for i := 0; i < n; i++ {
+		counts[Zipf(2, 1, 10)(c).(int)]++
+	}
+	if counts[0] < counts[1] || counts[1] < counts[5] {
+		t.Errorf("Zipf counts not decreasing: %v", counts)
+	}
+
+	// Integer variants can be used as Array lengths.
+	for i := 0; i < 100; i++ {
+		if len(Array(Poisson(2), 1)(nil).(Slice)) < 0 {
+			t.Fatal("negative length")
+		}
+		if l := len(Array(Round(Clamp(1, 3, Normal(2, 1))), 1)(nil).(Slice)); l < 1 || l > 3 {
+			t.Fatalf("length %d out of range", l)
+		}
+	}
+
+	// Without an int in range, ints are clamped as float64 values.
+	if v := Clamp(0.5, 0.7, 3)(nil); v != 0.7 {
+		t.Errorf("Clamp with no int in range: got %#v", v)
+	}
+
+	// Infinite and very large bounds saturate to the int range.
+	for _, x := range []struct {
+		gen  Generator
+		want int
+	}{
+		{Clamp(0, math.Inf(1), 5), 5},
+		{Clamp(0, 1e30, 5), 5},
+		{Clamp(math.Inf(-1), 0, 5), 0},
+		{Clamp(math.Inf(-1), math.Inf(1), -5), -5},
+		{Clamp(10, math.Inf(1), 5), 10},
+		{Clamp(-1e30, -10, 5), -10},
+	} {
+		if v := x.gen(nil); v != x.want {
+			t.Errorf("Clamp with wide bounds: got %#v, wanted %d", v, x.want)
+		}
+	}
+	if v := Clamp(1e30, math.Inf(1), 5)(nil); v != 1e30 {
+		t.Errorf("Clamp beyond the int range: got %#v", v)
+	}
+
+	// Seeded output is reproducible.
+	g := Sequence(Normal(0, 1), LogNormal(0, 1), Exponential(1), Poisson(50), Zipf(1.5, 2, 100), Beta(1, 2), Triangular(0, 1, 2))
+	checkStringIs(t, g(NewContextWithSeed(7)).(Slice).String(), g(NewContextWithSeed(7)).(Slice).String(), "seeded distributions")
+}
+
+func TestDistributions_Panics(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]func(){
+		"Normal":        func() { Normal(0, -1) },
+		"LogNormal":     func() { LogNormal(0, -1) },
+		"Exponential":   func() { Exponential(0) },
+		"Poisson":       func() { Poisson(-1) },
+		"Poisson NaN":   func() { Poisson(math.NaN()) },
+		"Zipf s":        func() { Zipf(1, 1, 10) },
+		"Zipf v":        func() { Zipf(2, 0.5, 10) },
+		"Beta":          func() { Beta(0, 1) },
+		"Triangular":    func() { Triangular(0, 5, 4) },
+		"Clamp":         func() { Clamp(2, 1, 1) },
+		"Clamp NaN":     func() { Clamp(math.NaN(), 1, 1) },
+		"Clamp runtime": func() { Clamp(0, 1, "x")(nil) },
+		"Round runtime": func() { Round("x")(nil) },
+	}
+	for label, f := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected panic", label)
+				}
+			}()
+			f()
+		}()
+	}
+}
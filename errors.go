@@ -0,0 +1,134 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"fmt"
+)
+
+// A GenerateError describes a failure to construct a Generator or to generate
+// a value, such as a template argument of the wrong type.  Generators panic
+// with a *GenerateError when they are given invalid input; Generate and
+// Construct recover those panics and return them as errors instead.
+type GenerateError struct {
+	// Path is a JSON Pointer to the value being generated when the failure
+	// occurred.  It is empty for failures at the top level or during
+	// construction.
+	Path string
+
+	// Msg describes the failure.
+	Msg string
+
+	// Expected and Actual describe the required type and the type (and, for
+	// scalars, the value) that was provided instead.  They are empty if the
+	// failure isn't a type mismatch.
+	Expected string
+	Actual   string
+
+	// Err is the underlying error, if the failure was caused by one.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *GenerateError) Error() string {
+	msg := e.Msg
+	if e.Expected != "" {
+		msg = fmt.Sprintf("%s: expected %s, got %s", msg, e.Expected, e.Actual)
+	}
+	if e.Path != "" {
+		msg = fmt.Sprintf("%s (at path %q)", msg, e.Path)
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error, if any.
+func (e *GenerateError) Unwrap() error {
+	return e.Err
+}
+
+// An ErrorGenerator is a function for producing arbitrary values that reports
+// failures as errors instead of panicking.  An ErrorGenerator may be used
+// anywhere a Generator may be used in a template; if it returns an error, the
+// enclosing Generator panics with a *GenerateError.
+type ErrorGenerator func(*Context) (interface{}, error)
+
+// Safe converts a Generator into an ErrorGenerator that recovers any panic
+// raised while generating and returns it as a *GenerateError.
+func Safe(g Generator) ErrorGenerator {
+	return func(c *Context) (interface{}, error) {
+		return Generate(c, g)
+	}
+}
+
+// Generate calls a Generator with a Context and returns the result.  If the
+// Generator panics, the panic is recovered and returned as a *GenerateError
+// giving the path to the value that failed.  If the Context is nil, a new
+// Context is used.  The Context's depth and path are restored on failure, so
+// the Context may be reused.
+func Generate(c *Context, g Generator) (v interface{}, err error) {
+	if c == nil {
+		c = NewContext()
+	}
+	depth, pathLen := c.Depth, len(c.path)
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredError(c, r)
+			c.Depth, c.path = depth, c.path[:pathLen]
+		}
+	}()
+	return g(c), nil
+}
+
+// Construct calls a function that builds a Generator, such as a closure around
+// a call to Object or Int, and returns the Generator.  If construction panics
+// because of invalid arguments, the panic is recovered and returned as a
+// *GenerateError.
+//
+//     g, err := jfdi.Construct(func() jfdi.Generator { return jfdi.Int(lo, hi) })
+func Construct(build func() Generator) (g Generator, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			g, err = nil, recoveredError(nil, r)
+		}
+	}()
+	return build(), nil
+}
+
+// recoveredError converts a recovered panic into a *GenerateError.  If the
+// panic did not come from a GenerateError, the Context (if any) supplies the
+// path.
+func recoveredError(c *Context, r interface{}) *GenerateError {
+	if e, ok := r.(*GenerateError); ok {
+		return e
+	}
+	e := &GenerateError{}
+	if c != nil {
+		e.Path = c.Path()
+	}
+	if err, ok := r.(error); ok {
+		e.Msg = err.Error()
+		e.Err = err
+	} else {
+		e.Msg = fmt.Sprint(r)
+	}
+	return e
+}
+
+// mustGenerator converts an ErrorGenerator into a Generator that panics with a
+// *GenerateError if the ErrorGenerator returns an error.
+func (f ErrorGenerator) mustGenerator() Generator {
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		v, err := f(c)
+		if err != nil {
+			c.report(recoveredError(c, err))
+		}
+		return v
+	}
+}
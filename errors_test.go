@@ -0,0 +1,96 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	v, err := Generate(nil, Object(Map{"x": Array(2, 23)}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkStringIs(t, v.(Map).String(), `{"x":[23,23]}`, "successful Generate")
+
+	cases := []struct {
+		gen      Generator
+		path     string
+		expected string
+		actual   string
+	}{
+		{Object(Map{"a": Array("three", 1)}), "/a", "non-negative int", `string "three"`},
+		{Object(Map{"a": Array(Int(-2, -2), 1)}), "/a", "non-negative int", "int -2"},
+		{Array(1, Join(Words(2), 42)), "/0", "string", "int 42"},
+		{Object(Map{"a": Join(Int(1, 1), " ")}), "/a", "[]string", "int 1"},
+		{Object(Map{"a": Object(Map{"b": Object(42)})}), "/a/b", "Map", "int 42"},
+		{Sequence(1, Words(nil)), "/1", "non-negative int", "nil"},
+	}
+
+	c := NewContext()
+	for _, x := range cases {
+		_, err := Generate(c, x.gen)
+		ge, ok := err.(*GenerateError)
+		if !ok {
+			t.Errorf("expected *GenerateError, got %v", err)
+			continue
+		}
+		checkStringIs(t, ge.Path, x.path, "error path")
+		checkStringIs(t, ge.Expected, x.expected, "error expected")
+		checkStringIs(t, ge.Actual, x.actual, "error actual")
+		checkStringIs(t, c.Path(), "", "path restored")
+		if c.Depth != 0 {
+			t.Errorf("depth not restored: got %d", c.Depth)
+		}
+	}
+
+	// Arbitrary runtime panics are converted too.
+	_, err = Generate(nil, Array(1, func(*Context) interface{} { panic("boom") }))
+	if err == nil || err.Error() != `boom (at path "/0")` {
+		t.Errorf("unexpected error for runtime panic: %v", err)
+	}
+}
+
+func TestErrorGenerator(t *testing.T) {
+	t.Parallel()
+
+	ok := ErrorGenerator(func(*Context) (interface{}, error) { return 42, nil })
+	bad := ErrorGenerator(func(*Context) (interface{}, error) { return nil, errors.New("bad") })
+
+	v, err := Generate(nil, Object(Map{"x": ok}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkStringIs(t, v.(Map).String(), `{"x":42}`, "ErrorGenerator in template")
+
+	_, err = Generate(nil, Object(Map{"x": bad}))
+	if err == nil || err.Error() != `bad (at path "/x")` {
+		t.Errorf("unexpected error from ErrorGenerator: %v", err)
+	}
+
+	_, err = Safe(Array("x", 1))(nil)
+	if err == nil {
+		t.Errorf("Safe didn't return an error")
+	}
+}
+
+func TestConstruct(t *testing.T) {
+	t.Parallel()
+
+	g, err := Construct(func() Generator { return Int(1, 1) })
+	if err != nil || g(nil).(int) != 1 {
+		t.Errorf("unexpected Construct result: %v", err)
+	}
+
+	g, err = Construct(func() Generator { return Int(2, 1) })
+	if err == nil || g != nil {
+		t.Errorf("Construct didn't return an error for invalid arguments")
+	}
+}
@@ -2,7 +2,4 @@ module github.com/xdg-go/jfdi
 
 go 1.11
 
-require (
-	github.com/corpix/uarand v0.1.1 // indirect
-	github.com/icrowley/fake v0.0.0-20180203215853-4178557ae428
-)
+require gopkg.in/yaml.v3 v3.0.1
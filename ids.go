@@ -0,0 +1,105 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
+
+// defaultIDTime generates timestamps for UUIDv7 and ObjectID when no
+// timestamp model is given.  A fixed range keeps seeded output reproducible.
+var defaultIDTime = Time(
+	time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+)
+
+// UUIDv4 returns a generator of random (version 4) UUID strings in canonical
+// form, such as "f47ac10b-58cc-4372-a567-0e02b2c3d479", with the version and
+// variant bits set.
+func UUIDv4() Generator {
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		var u [16]byte
+		binary.BigEndian.PutUint64(u[:8], c.Rand.Uint64())
+		binary.BigEndian.PutUint64(u[8:], c.Rand.Uint64())
+		return formatUUID(u, 4)
+	}
+}
+
+// UUIDv7 returns a generator of time-ordered (version 7) UUID strings in
+// canonical form.  The first 48 bits are a Unix timestamp in milliseconds
+// taken from the model, which must be a time.Time or produce one, such as a
+// Time generator.  If the model is nil, timestamps are chosen from 2020
+// through 2024.  The remaining bits are random, apart from the version and
+// variant bits.
+func UUIDv7(timestamp interface{}) Generator {
+	if timestamp == nil {
+		timestamp = defaultIDTime
+	}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		t := idTime(c, timestamp)
+		ms := uint64(t.Unix())*1000 + uint64(t.Nanosecond())/uint64(time.Millisecond)
+		var u [16]byte
+		binary.BigEndian.PutUint64(u[:8], ms<<16|c.Rand.Uint64()&0xffff)
+		binary.BigEndian.PutUint64(u[8:], c.Rand.Uint64())
+		return formatUUID(u, 7)
+	}
+}
+
+// formatUUID sets the version and RFC 4122 variant bits and formats a UUID.
+func formatUUID(u [16]byte, version byte) string {
+	u[6] = u[6]&0x0f | version<<4
+	u[8] = u[8]&0x3f | 0x80
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf)
+}
+
+// ObjectID returns a generator of MongoDB ObjectID hex strings, such as
+// "5e0be100a3f2c81b9d4e7a02".  The first four bytes are a Unix timestamp in
+// seconds taken from the model, which must be a time.Time or produce one.  If
+// the model is nil, timestamps are chosen from 2020 through 2024.  The
+// remaining eight bytes are random.
+func ObjectID(timestamp interface{}) Generator {
+	if timestamp == nil {
+		timestamp = defaultIDTime
+	}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		t := idTime(c, timestamp)
+		var id [12]byte
+		binary.BigEndian.PutUint32(id[:4], uint32(t.Unix()))
+		binary.BigEndian.PutUint64(id[4:], c.Rand.Uint64())
+		return hex.EncodeToString(id[:])
+	}
+}
+
+func idTime(c *Context, model interface{}) time.Time {
+	v := expand(c, model)
+	t, ok := v.(time.Time)
+	if !ok {
+		c.fail("timestamp must be or generate a time.Time", "time.Time", v)
+	}
+	return t
+}
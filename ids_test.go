@@ -0,0 +1,88 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestUUIDv4(t *testing.T) {
+	t.Parallel()
+
+	re := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	g := UUIDv4()
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		s := g(nil).(string)
+		if !re.MatchString(s) {
+			t.Fatalf("invalid UUIDv4: %s", s)
+		}
+		if seen[s] {
+			t.Fatalf("duplicate UUIDv4: %s", s)
+		}
+		seen[s] = true
+	}
+
+	checkStringIs(t, g(NewContextWithSeed(42)).(string), g(NewContextWithSeed(42)).(string), "seeded UUIDv4")
+}
+
+func TestUUIDv7(t *testing.T) {
+	t.Parallel()
+
+	re := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	ts := time.Date(2019, 3, 14, 15, 9, 26, 535000000, time.UTC)
+	for _, g := range []Generator{UUIDv7(nil), UUIDv7(ts), UUIDv7(Time(ts, ts.Add(time.Hour)))} {
+		for i := 0; i < 100; i++ {
+			if s := g(nil).(string); !re.MatchString(s) {
+				t.Fatalf("invalid UUIDv7: %s", s)
+			}
+		}
+	}
+
+	// The timestamp is in the first 48 bits.
+	s := UUIDv7(ts)(nil).(string)
+	ms, _ := strconv.ParseInt(s[0:8]+s[9:13], 16, 64)
+	if ms != 1552576166535 {
+		t.Errorf("wrong UUIDv7 timestamp: %s", s)
+	}
+
+	g := UUIDv7(nil)
+	checkStringIs(t, g(NewContextWithSeed(42)).(string), g(NewContextWithSeed(42)).(string), "seeded UUIDv7")
+
+	if _, err := Generate(nil, UUIDv7("x")); err == nil {
+		t.Errorf("expected error for invalid timestamp")
+	}
+}
+
+func TestObjectID(t *testing.T) {
+	t.Parallel()
+
+	re := regexp.MustCompile(`^[0-9a-f]{24}$`)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	g := ObjectID(nil)
+	for i := 0; i < 100; i++ {
+		s := g(nil).(string)
+		if !re.MatchString(s) {
+			t.Fatalf("invalid ObjectID: %s", s)
+		}
+		secs, _ := strconv.ParseInt(s[:8], 16, 64)
+		if ts := time.Unix(secs, 0); ts.Before(start) || ts.After(end) {
+			t.Fatalf("ObjectID timestamp %v out of range", ts)
+		}
+	}
+
+	s := ObjectID(time.Unix(0x5e0be100, 0))(nil).(string)
+	if s[:8] != "5e0be100" {
+		t.Errorf("wrong ObjectID timestamp: %s", s)
+	}
+
+	checkStringIs(t, g(NewContextWithSeed(42)).(string), g(NewContextWithSeed(42)).(string), "seeded ObjectID")
+}
@@ -0,0 +1,829 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// schemaMaxDepth is the container depth beyond which schema Generators
+	// omit optional properties and generate the fewest array items allowed,
+	// so that recursive schemas terminate.
+	schemaMaxDepth = 6
+
+	// schemaMaxNesting limits expansion of $ref and allOf chains that are
+	// merged at compile time.
+	schemaMaxNesting = 32
+
+	// schemaMaxRetries limits attempts to satisfy constraints, like
+	// uniqueItems, that are checked after generating a value.
+	schemaMaxRetries = 100
+
+	// schemaRange is the width of the numeric range used when a schema
+	// gives at most one bound.
+	schemaRange = 1000
+)
+
+// FromJSONSchema compiles a JSON Schema document (draft-07 or draft 2020-12)
+// into a Generator of values that validate against the schema.  JSON objects
+// are generated as Maps and arrays as Slices.
+//
+// The following keywords are supported:
+//
+//     type, enum, const
+//     properties, required
+//     items, prefixItems, additionalItems, minItems, maxItems, uniqueItems
+//     minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf
+//     minLength, maxLength, pattern
+//     allOf, anyOf, oneOf
+//     $ref (local references only, such as "#/$defs/address")
+//
+// Other keywords are ignored.  Required properties are always generated;
+// optional properties are each generated half the time.  Recursive schemas
+// stop generating optional properties and extra array items once the
+// generated data is nested deeply enough.  For oneOf, a branch is chosen at
+// random and is not checked for exclusivity against the other branches.
+//
+// An error is returned if the document isn't valid JSON, uses a $ref that
+// can't be resolved, or has constraints that can't be satisfied.
+func FromJSONSchema(doc []byte) (Generator, error) {
+	var root interface{}
+	if err := json.Unmarshal(doc, &root); err != nil {
+		return nil, fmt.Errorf("invalid JSON Schema document: %v", err)
+	}
+	sc := &schemaCompiler{root: root, refs: make(map[string]Generator)}
+	return sc.compile(root, "#")
+}
+
+type schemaCompiler struct {
+	root interface{}
+	refs map[string]Generator
+}
+
+// schemaError reports an error in the schema at the given location.
+func schemaError(loc string, format string, args ...interface{}) error {
+	return fmt.Errorf("schema at %q: %s", loc, fmt.Sprintf(format, args...))
+}
+
+// annotationKeywords don't constrain values, so a $ref alongside only these
+// can be compiled lazily to support recursion.
+var annotationKeywords = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "$defs": true,
+	"definitions": true, "title": true, "description": true,
+	"default": true, "examples": true,
+}
+
+// constrained reports whether a schema has keywords other than annotations
+// and the given keyword.
+func constrained(m map[string]interface{}, except string) bool {
+	for k := range m {
+		if k != except && !annotationKeywords[k] {
+			return true
+		}
+	}
+	return false
+}
+
+func (sc *schemaCompiler) compile(s interface{}, loc string) (Generator, error) {
+	m, err := schemaMap(s, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref, ok := m["$ref"]; ok {
+		if !constrained(m, "$ref") {
+			refStr, ok := ref.(string)
+			if !ok {
+				return nil, schemaError(loc, "$ref must be a string")
+			}
+			return sc.ref(refStr, loc)
+		}
+	}
+	if _, ok := m["$ref"]; ok || m["allOf"] != nil {
+		if m, err = sc.flatten(m, loc, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	if v, ok := m["const"]; ok {
+		return func(*Context) interface{} { return copyJSON(v) }, nil
+	}
+	if v, ok := m["enum"]; ok {
+		xs, ok := v.([]interface{})
+		if !ok || len(xs) == 0 {
+			return nil, schemaError(loc, "enum must be a non-empty array")
+		}
+		return func(c *Context) interface{} {
+			if c == nil {
+				c = NewContext()
+			}
+			return copyJSON(xs[c.Rand.Intn(len(xs))])
+		}, nil
+	}
+
+	for _, kw := range []string{"oneOf", "anyOf"} {
+		if v, ok := m[kw]; ok {
+			return sc.compileChoice(m, kw, v, loc)
+		}
+	}
+
+	types, err := schemaTypes(m, loc)
+	if err != nil {
+		return nil, err
+	}
+	gens := make([]interface{}, len(types))
+	for i, t := range types {
+		if gens[i], err = sc.compileType(m, t, loc); err != nil {
+			return nil, err
+		}
+	}
+	if len(gens) == 1 {
+		return gens[0].(Generator), nil
+	}
+	return Pick(gens...), nil
+}
+
+// ref returns a Generator for a $ref.  Generators are compiled once per
+// reference and looked up when called, so references may be recursive.
+func (sc *schemaCompiler) ref(ref string, loc string) (Generator, error) {
+	if g, ok := sc.refs[ref]; ok {
+		return g, nil
+	}
+	var g Generator
+	sc.refs[ref] = func(c *Context) interface{} { return g(c) }
+	target, err := sc.resolve(ref, loc)
+	if err != nil {
+		return nil, err
+	}
+	if g, err = sc.compile(target, ref); err != nil {
+		return nil, err
+	}
+	return sc.refs[ref], nil
+}
+
+// resolve finds the schema for a local $ref, which must be "#" or a JSON
+// Pointer fragment like "#/$defs/address".
+func (sc *schemaCompiler) resolve(ref string, loc string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, schemaError(loc, "unsupported $ref %q: only local references are supported", ref)
+	}
+	ptr := ref[1:]
+	if ptr == "" {
+		return sc.root, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, schemaError(loc, "unsupported $ref %q: must be a JSON Pointer", ref)
+	}
+	cur := sc.root
+	for _, tok := range strings.Split(ptr[1:], "/") {
+		tok = strings.Replace(strings.Replace(tok, "~1", "/", -1), "~0", "~", -1)
+		switch x := cur.(type) {
+		case map[string]interface{}:
+			v, ok := x[tok]
+			if !ok {
+				return nil, schemaError(loc, "unresolvable $ref %q", ref)
+			}
+			cur = v
+		case []interface{}:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(x) {
+				return nil, schemaError(loc, "unresolvable $ref %q", ref)
+			}
+			cur = x[i]
+		default:
+			return nil, schemaError(loc, "unresolvable $ref %q", ref)
+		}
+	}
+	return cur, nil
+}
+
+// flatten merges a schema's $ref target and allOf subschemas into a single
+// schema without $ref or allOf at the top level.
+func (sc *schemaCompiler) flatten(s interface{}, loc string, n int) (map[string]interface{}, error) {
+	if n > schemaMaxNesting {
+		return nil, schemaError(loc, "$ref or allOf nested too deeply (recursive?)")
+	}
+	m, err := schemaMap(s, loc)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]interface{})
+	if v, ok := m["$ref"]; ok {
+		ref, ok := v.(string)
+		if !ok {
+			return nil, schemaError(loc, "$ref must be a string")
+		}
+		target, err := sc.resolve(ref, loc)
+		if err != nil {
+			return nil, err
+		}
+		t, err := sc.flatten(target, ref, n+1)
+		if err != nil {
+			return nil, err
+		}
+		mergeSchema(out, t)
+	}
+	if v, ok := m["allOf"]; ok {
+		subs, ok := v.([]interface{})
+		if !ok {
+			return nil, schemaError(loc, "allOf must be an array")
+		}
+		for i, sub := range subs {
+			f, err := sc.flatten(sub, fmt.Sprintf("%s/allOf/%d", loc, i), n+1)
+			if err != nil {
+				return nil, err
+			}
+			mergeSchema(out, f)
+		}
+	}
+	rest := make(map[string]interface{})
+	for k, v := range m {
+		if k != "$ref" && k != "allOf" {
+			rest[k] = v
+		}
+	}
+	mergeSchema(out, rest)
+	return out, nil
+}
+
+// mergeSchema merges src into dst so that values satisfying dst satisfy both
+// schemas, as closely as is practical for generation.
+func mergeSchema(dst, src map[string]interface{}) {
+	for k, v := range src {
+		old, ok := dst[k]
+		if !ok {
+			dst[k] = v
+			continue
+		}
+		switch k {
+		case "properties":
+			props := make(map[string]interface{})
+			for pk, pv := range asObject(old) {
+				props[pk] = pv
+			}
+			for pk, pv := range asObject(v) {
+				if prev, ok := props[pk]; ok {
+					props[pk] = map[string]interface{}{"allOf": []interface{}{prev, pv}}
+				} else {
+					props[pk] = pv
+				}
+			}
+			dst[k] = props
+		case "required":
+			seen := make(map[interface{}]bool)
+			var req []interface{}
+			for _, x := range append(asArray(old), asArray(v)...) {
+				if !seen[x] {
+					seen[x] = true
+					req = append(req, x)
+				}
+			}
+			dst[k] = req
+		case "minimum", "exclusiveMinimum", "minLength", "minItems":
+			a, aok := old.(float64)
+			b, bok := v.(float64)
+			if aok && bok {
+				dst[k] = math.Max(a, b)
+			} else {
+				dst[k] = v
+			}
+		case "maximum", "exclusiveMaximum", "maxLength", "maxItems":
+			a, aok := old.(float64)
+			b, bok := v.(float64)
+			if aok && bok {
+				dst[k] = math.Min(a, b)
+			} else {
+				dst[k] = v
+			}
+		case "type":
+			dst[k] = intersectTypes(typeList(old), typeList(v))
+		case "enum":
+			var both []interface{}
+			for _, x := range asArray(old) {
+				for _, y := range asArray(v) {
+					if reflect.DeepEqual(x, y) {
+						both = append(both, x)
+						break
+					}
+				}
+			}
+			dst[k] = both
+		default:
+			dst[k] = v
+		}
+	}
+}
+
+func (sc *schemaCompiler) compileChoice(m map[string]interface{}, kw string, v interface{}, loc string) (Generator, error) {
+	branches, ok := v.([]interface{})
+	if !ok || len(branches) == 0 {
+		return nil, schemaError(loc, "%s must be a non-empty array", kw)
+	}
+	base := make(map[string]interface{})
+	for k, x := range m {
+		if k != kw {
+			base[k] = x
+		}
+	}
+	gens := make([]interface{}, len(branches))
+	for i, b := range branches {
+		bloc := fmt.Sprintf("%s/%s/%d", loc, kw, i)
+		// Only merge the branch with the rest of the schema if needed, so
+		// that a branch that is just a $ref is compiled lazily.
+		var err error
+		if constrained(m, kw) {
+			if b, err = sc.flatten(map[string]interface{}{"allOf": []interface{}{base, b}}, bloc, 0); err != nil {
+				return nil, err
+			}
+		}
+		if gens[i], err = sc.compile(b, bloc); err != nil {
+			return nil, err
+		}
+	}
+	return Pick(gens...), nil
+}
+
+func (sc *schemaCompiler) compileType(m map[string]interface{}, t string, loc string) (Generator, error) {
+	switch t {
+	case "null":
+		return zeroGenerator, nil
+	case "boolean":
+		return Bool(), nil
+	case "integer":
+		return schemaInteger(m, loc)
+	case "number":
+		return schemaNumber(m, loc)
+	case "string":
+		return schemaString(m, loc)
+	case "array":
+		return sc.compileArray(m, loc)
+	case "object":
+		return sc.compileObject(m, loc)
+	default:
+		return nil, schemaError(loc, "unknown type %q", t)
+	}
+}
+
+// schemaBounds returns the numeric bounds of a schema and whether each is
+// exclusive.  Both draft-04 style boolean and numeric exclusive bounds are
+// understood.
+func schemaBounds(m map[string]interface{}) (lo, hi float64, hasLo, hasHi, exLo, exHi bool) {
+	lo, hasLo = m["minimum"].(float64)
+	hi, hasHi = m["maximum"].(float64)
+	if x, ok := m["exclusiveMinimum"].(float64); ok && (!hasLo || x >= lo) {
+		lo, hasLo, exLo = x, true, true
+	} else if b, ok := m["exclusiveMinimum"].(bool); ok && b && hasLo {
+		exLo = true
+	}
+	if x, ok := m["exclusiveMaximum"].(float64); ok && (!hasHi || x <= hi) {
+		hi, hasHi, exHi = x, true, true
+	} else if b, ok := m["exclusiveMaximum"].(bool); ok && b && hasHi {
+		exHi = true
+	}
+	switch {
+	case !hasLo && !hasHi:
+		lo, hi = 0, schemaRange
+	case !hasLo:
+		lo = hi - schemaRange
+	case !hasHi:
+		hi = lo + schemaRange
+	}
+	return lo, hi, hasLo, hasHi, exLo, exHi
+}
+
+func schemaInteger(m map[string]interface{}, loc string) (Generator, error) {
+	flo, fhi, _, _, exLo, exHi := schemaBounds(m)
+	lo, hi := math.Ceil(flo), math.Floor(fhi)
+	if exLo && lo == flo {
+		lo++
+	}
+	if exHi && hi == fhi {
+		hi--
+	}
+	lo, hi, ok := intBounds(lo, hi)
+	if !ok {
+		return nil, schemaError(loc, "integer bounds are too large")
+	}
+	step := 1.0
+	if x, ok := m["multipleOf"].(float64); ok {
+		if x <= 0 {
+			return nil, schemaError(loc, "multipleOf must be positive")
+		}
+		step = integerStep(x)
+	}
+	lo, hi = math.Ceil(lo/step), math.Floor(hi/step)
+	if lo > hi {
+		return nil, schemaError(loc, "no integer satisfies the bounds")
+	}
+	k := Int(int(lo), int(hi))
+	n := int(step)
+	return func(c *Context) interface{} {
+		return k(c).(int) * n
+	}, nil
+}
+
+// integerStep returns the least common multiple of 1 and x, the smallest
+// positive integer that is a multiple of x.  X is read as the decimal number
+// it was written as, so that e.g. 0.1 gives 1 and 2.5 gives 5.
+func integerStep(x float64) float64 {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(x, 'g', -1, 64))
+	step, _ := new(big.Rat).SetInt(r.Num()).Float64()
+	return step
+}
+
+// intBounds clamps integral float64 bounds to the int range.  It reports false
+// if no int is within the bounds.
+func intBounds(lo, hi float64) (float64, float64, bool) {
+	if lo >= intRange || hi < -intRange {
+		return lo, hi, false
+	}
+	if lo < -intRange {
+		lo = -intRange
+	}
+	if hi >= intRange {
+		hi = math.Floor(math.Nextafter(intRange, 0))
+	}
+	return lo, hi, true
+}
+
+func schemaNumber(m map[string]interface{}, loc string) (Generator, error) {
+	lo, hi, _, _, exLo, exHi := schemaBounds(m)
+	if x, ok := m["multipleOf"].(float64); ok {
+		if x <= 0 {
+			return nil, schemaError(loc, "multipleOf must be positive")
+		}
+		klo, khi := math.Ceil(lo/x), math.Floor(hi/x)
+		if exLo && klo*x == lo {
+			klo++
+		}
+		if exHi && khi*x == hi {
+			khi--
+		}
+		if klo > khi {
+			return nil, schemaError(loc, "no multiple of %v satisfies the bounds", x)
+		}
+		klo, khi, ok := intBounds(klo, khi)
+		if !ok {
+			return nil, schemaError(loc, "number bounds are too large for multipleOf")
+		}
+		k := Int(int(klo), int(khi))
+		return func(c *Context) interface{} {
+			return float64(k(c).(int)) * x
+		}, nil
+	}
+	if exLo {
+		lo = math.Nextafter(lo, math.Inf(1))
+	}
+	if lo > hi || (exHi && lo == hi) {
+		return nil, schemaError(loc, "no number satisfies the bounds")
+	}
+	if lo == hi {
+		return func(*Context) interface{} { return lo }, nil
+	}
+	// Float64 generates in [lo,hi), so an exclusive maximum is satisfied.
+	return Float64(lo, hi), nil
+}
+
+func schemaString(m map[string]interface{}, loc string) (Generator, error) {
+	minLen, maxLen := 0, -1
+	if x, ok := m["minLength"].(float64); ok {
+		minLen = int(x)
+	}
+	if x, ok := m["maxLength"].(float64); ok {
+		maxLen = int(x)
+	}
+	if maxLen >= 0 && minLen > maxLen {
+		return nil, schemaError(loc, "minLength is greater than maxLength")
+	}
+
+	if v, ok := m["pattern"]; ok {
+		pattern, ok := v.(string)
+		if !ok {
+			return nil, schemaError(loc, "pattern must be a string")
+		}
+		g, err := regexGenerator(pattern, defaultMaxRepeat)
+		if err != nil {
+			return nil, schemaError(loc, "invalid pattern: %v", err)
+		}
+		return func(c *Context) interface{} {
+			if c == nil {
+				c = NewContext()
+			}
+			for i := 0; i < schemaMaxRetries; i++ {
+				s := g(c).(string)
+				n := utf8.RuneCountInString(s)
+				if n >= minLen && (maxLen < 0 || n <= maxLen) {
+					return s
+				}
+			}
+			c.report(&GenerateError{
+				Path: c.Path(),
+				Msg:  fmt.Sprintf("could not generate a string matching %q within length bounds", pattern),
+			})
+			return ""
+		}, nil
+	}
+
+	if maxLen < 0 {
+		maxLen = minLen + 10
+	}
+	length := Int(minLen, maxLen)
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		n := length(c).(int)
+		var sb strings.Builder
+		for sb.Len() < n {
+			if sb.Len() > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(loremWord(c.Rand))
+		}
+		return sb.String()[:n]
+	}, nil
+}
+
+func (sc *schemaCompiler) compileArray(m map[string]interface{}, loc string) (Generator, error) {
+	var prefix []Generator
+	var rest Generator
+	restAllowed := true
+
+	compileRest := func(v interface{}, kw string) error {
+		if b, ok := v.(bool); ok && !b {
+			restAllowed = false
+			return nil
+		}
+		g, err := sc.compile(v, loc+"/"+kw)
+		rest = g
+		return err
+	}
+	compilePrefix := func(v interface{}, kw string) error {
+		for i, x := range asArray(v) {
+			g, err := sc.compile(x, fmt.Sprintf("%s/%s/%d", loc, kw, i))
+			if err != nil {
+				return err
+			}
+			prefix = append(prefix, g)
+		}
+		return nil
+	}
+
+	var err error
+	if p, ok := m["prefixItems"]; ok {
+		err = compilePrefix(p, "prefixItems")
+		if v, ok := m["items"]; ok && err == nil {
+			err = compileRest(v, "items")
+		}
+	} else if v, ok := m["items"].([]interface{}); ok {
+		err = compilePrefix(v, "items")
+		if v, ok := m["additionalItems"]; ok && err == nil {
+			err = compileRest(v, "additionalItems")
+		}
+	} else if v, ok := m["items"]; ok {
+		err = compileRest(v, "items")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rest == nil && restAllowed {
+		if rest, err = sc.compile(true, loc); err != nil {
+			return nil, err
+		}
+	}
+
+	minItems, maxItems := 0, -1
+	if x, ok := m["minItems"].(float64); ok {
+		minItems = int(x)
+	}
+	if x, ok := m["maxItems"].(float64); ok {
+		maxItems = int(x)
+	}
+	if !restAllowed && (maxItems < 0 || maxItems > len(prefix)) {
+		maxItems = len(prefix)
+	}
+	if maxItems < 0 {
+		maxItems = minItems + 3
+	}
+	if minItems > maxItems {
+		return nil, schemaError(loc, "minItems can't be satisfied")
+	}
+	unique, _ := m["uniqueItems"].(bool)
+
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		c.Depth++
+		defer func() { c.Depth-- }()
+
+		n := minItems
+		if c.Depth <= schemaMaxDepth {
+			n += c.Rand.Intn(maxItems - minItems + 1)
+		}
+		output := make(Slice, n)
+		seen := make(map[string]bool)
+		for i := 0; i < n; i++ {
+			g := rest
+			if i < len(prefix) {
+				g = prefix[i]
+			}
+			c.pushIndex(i)
+			for try := 0; ; try++ {
+				output[i] = g(c)
+				if !unique {
+					break
+				}
+				key := jsonKey(output[i])
+				if !seen[key] {
+					seen[key] = true
+					break
+				}
+				if try == schemaMaxRetries {
+					c.report(&GenerateError{Path: c.Path(), Msg: "could not generate a unique array item"})
+					break
+				}
+			}
+			c.pop()
+		}
+		return output
+	}, nil
+}
+
+func (sc *schemaCompiler) compileObject(m map[string]interface{}, loc string) (Generator, error) {
+	required := make(map[string]bool)
+	for _, x := range asArray(m["required"]) {
+		if k, ok := x.(string); ok {
+			required[k] = true
+		}
+	}
+
+	props := asObject(m["properties"])
+	gens := make(map[string]Generator, len(props))
+	for k, v := range props {
+		g, err := sc.compile(v, loc+"/properties/"+k)
+		if err != nil {
+			return nil, err
+		}
+		gens[k] = g
+	}
+	for k := range required {
+		if _, ok := gens[k]; !ok {
+			g, err := sc.compile(true, loc+"/required")
+			if err != nil {
+				return nil, err
+			}
+			gens[k] = g
+		}
+	}
+
+	keys := make([]string, 0, len(gens))
+	for k := range gens {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return Object(func(c *Context) interface{} {
+		model := Map{}
+		for _, k := range keys {
+			if required[k] || (c.Depth <= schemaMaxDepth && c.Rand.Intn(2) == 0) {
+				model[k] = gens[k]
+			}
+		}
+		return model
+	}), nil
+}
+
+// schemaMap converts a schema, which may be a boolean, into a map.
+func schemaMap(s interface{}, loc string) (map[string]interface{}, error) {
+	switch x := s.(type) {
+	case bool:
+		if !x {
+			return nil, schemaError(loc, "false schema can't be satisfied")
+		}
+		return map[string]interface{}{}, nil
+	case map[string]interface{}:
+		return x, nil
+	default:
+		return nil, schemaError(loc, "schema must be an object or boolean")
+	}
+}
+
+var scalarTypes = []string{"null", "boolean", "integer", "number", "string"}
+
+// schemaTypes returns the types a schema allows, inferring them from other
+// keywords if "type" is absent.
+func schemaTypes(m map[string]interface{}, loc string) ([]string, error) {
+	if v, ok := m["type"]; ok {
+		types := typeList(v)
+		if len(types) == 0 {
+			return nil, schemaError(loc, "no type satisfies the schema")
+		}
+		return types, nil
+	}
+	has := func(kws ...string) bool {
+		for _, kw := range kws {
+			if _, ok := m[kw]; ok {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case has("properties", "required", "additionalProperties"):
+		return []string{"object"}, nil
+	case has("items", "prefixItems", "minItems", "maxItems", "uniqueItems"):
+		return []string{"array"}, nil
+	case has("minLength", "maxLength", "pattern"):
+		return []string{"string"}, nil
+	case has("minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf"):
+		return []string{"number"}, nil
+	}
+	return scalarTypes, nil
+}
+
+func typeList(v interface{}) []string {
+	if s, ok := v.(string); ok {
+		return []string{s}
+	}
+	var types []string
+	for _, x := range asArray(v) {
+		if s, ok := x.(string); ok {
+			types = append(types, s)
+		}
+	}
+	return types
+}
+
+// intersectTypes returns the types allowed by both lists.  An integer is also
+// a number.
+func intersectTypes(a, b []string) []interface{} {
+	out := []interface{}{}
+	for _, x := range a {
+		for _, y := range b {
+			switch {
+			case x == y:
+				out = append(out, x)
+			case x == "integer" && y == "number", x == "number" && y == "integer":
+				out = append(out, "integer")
+			default:
+				continue
+			}
+			break
+		}
+	}
+	return out
+}
+
+func asObject(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func asArray(v interface{}) []interface{} {
+	xs, _ := v.([]interface{})
+	return xs
+}
+
+// copyJSON returns a copy of a decoded JSON value, converting objects and
+// arrays to Map and Slice.
+func copyJSON(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		m := make(Map, len(x))
+		for k, v := range x {
+			m[k] = copyJSON(v)
+		}
+		return m
+	case []interface{}:
+		s := make(Slice, len(x))
+		for i, v := range x {
+			s[i] = copyJSON(v)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// jsonKey returns a canonical string for comparing JSON values.
+func jsonKey(v interface{}) string {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%#v", v)
+	}
+	return string(buf)
+}
@@ -0,0 +1,250 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"math"
+	"regexp"
+	"testing"
+)
+
+const testSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["id", "sku", "kind", "version", "tags", "price", "owner"],
+	"properties": {
+		"id":      {"type": "integer", "minimum": 1, "exclusiveMaximum": 10},
+		"sku":     {"type": "string", "pattern": "^[A-Z]{2}\\d{4,6}(-[a-z]+)?$"},
+		"kind":    {"enum": ["book", "toy", 42]},
+		"version": {"const": {"major": 1}},
+		"tags":    {"type": "array", "items": {"type": "string", "minLength": 2, "maxLength": 4},
+		            "minItems": 1, "maxItems": 3, "uniqueItems": true},
+		"price":   {"type": "number", "exclusiveMinimum": 0, "maximum": 5, "multipleOf": 0.25},
+		"owner":   {"$ref": "#/$defs/person"},
+		"note":    {"type": ["string", "null"]}
+	},
+	"$defs": {
+		"person": {
+			"allOf": [
+				{"type": "object", "required": ["name"], "properties": {"name": {"type": "string", "maxLength": 5}}},
+				{"required": ["age"], "properties": {"age": {"type": "integer", "minimum": 18, "maximum": 65}}}
+			]
+		}
+	}
+}`
+
+func TestFromJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	g, err := FromJSONSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("compile error: %v", err)
+	}
+
+	sku := regexp.MustCompile(`^[A-Z]{2}\d{4,6}(-[a-z]+)?$`)
+	c := NewContext()
+	for i := 0; i < 100; i++ {
+		doc := g(c).(Map)
+
+		if id := doc["id"].(int); id < 1 || id >= 10 {
+			t.Errorf("id out of range: %d", id)
+		}
+		if s := doc["sku"].(string); !sku.MatchString(s) {
+			t.Errorf("sku doesn't match pattern: %q", s)
+		}
+		switch doc["kind"] {
+		case "book", "toy", 42.0:
+		default:
+			t.Errorf("kind not in enum: %v", doc["kind"])
+		}
+		checkStringIs(t, doc["version"].(Map).String(), `{"major":1}`, "const")
+
+		tags := doc["tags"].(Slice)
+		if len(tags) < 1 || len(tags) > 3 {
+			t.Errorf("wrong number of tags: %v", tags)
+		}
+		seen := make(map[string]bool)
+		for _, tag := range tags {
+			s := tag.(string)
+			if len(s) < 2 || len(s) > 4 || seen[s] {
+				t.Errorf("invalid tags: %v", tags)
+			}
+			seen[s] = true
+		}
+
+		if p := doc["price"].(float64); p <= 0 || p > 5 || p*4 != float64(int(p*4)) {
+			t.Errorf("invalid price: %v", p)
+		}
+
+		owner := doc["owner"].(Map)
+		if len(owner["name"].(string)) > 5 {
+			t.Errorf("owner name too long: %v", owner)
+		}
+		if age := owner["age"].(int); age < 18 || age > 65 {
+			t.Errorf("owner age out of range: %v", owner)
+		}
+
+		if note, ok := doc["note"]; ok && note != nil {
+			_ = note.(string)
+		}
+	}
+}
+
+func TestFromJSONSchema_Recursive(t *testing.T) {
+	t.Parallel()
+
+	// Children are optional, so recursion bottoms out.
+	g, err := FromJSONSchema([]byte(`{
+		"$ref": "#/definitions/node",
+		"definitions": {
+			"node": {
+				"type": "object",
+				"required": ["value"],
+				"properties": {
+					"value": {"type": "integer"},
+					"children": {"type": "array", "items": {"$ref": "#/definitions/node"}}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("compile error: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		if _, ok := g(nil).(Map)["value"].(int); !ok {
+			t.Errorf("recursive node missing value")
+		}
+	}
+
+	// A branch of oneOf can be a recursive reference.
+	g, err = FromJSONSchema([]byte(`{
+		"oneOf": [
+			{"type": "boolean"},
+			{"type": "array", "items": {"$ref": "#"}, "maxItems": 2}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("compile error: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		switch v := g(nil).(type) {
+		case bool, Slice:
+		default:
+			t.Errorf("unexpected oneOf value: %v", v)
+		}
+	}
+}
+
+func TestFromJSONSchema_Seeded(t *testing.T) {
+	t.Parallel()
+
+	g, err := FromJSONSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("compile error: %v", err)
+	}
+	c1 := NewContextWithSeed(42)
+	c2 := NewContextWithSeed(42)
+	for i := 0; i < 10; i++ {
+		checkStringIs(t, g(c1).(Map).String(), g(c2).(Map).String(), "seeded schema")
+	}
+}
+
+func TestFromJSONSchema_LargeIntegers(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		schema string
+		lo, hi int
+	}{
+		{`{"type": "integer", "minimum": 0, "maximum": 10000000000}`, 0, 10000000000},
+		{`{"type": "integer", "minimum": 1, "maximum": 9007199254740991}`, 1, 9007199254740991},
+		{`{"type": "integer", "minimum": -1e30, "maximum": 1e30}`, -maxInt - 1, maxInt},
+		{`{"type": "integer", "minimum": 1e18, "multipleOf": 1000, "maximum": 1e30}`, 1e18, maxInt},
+	}
+	for _, x := range cases {
+		g, err := FromJSONSchema([]byte(x.schema))
+		if err != nil {
+			t.Errorf("unexpected error for schema %s: %v", x.schema, err)
+			continue
+		}
+		c := NewContextWithSeed(42)
+		for i := 0; i < 100; i++ {
+			n := g(c).(int)
+			if n < x.lo || n > x.hi {
+				t.Fatalf("schema %s generated out of range value %d", x.schema, n)
+			}
+		}
+	}
+
+	if _, err := FromJSONSchema([]byte(`{"type": "integer", "minimum": 1e30}`)); err == nil {
+		t.Errorf("expected error for integer minimum beyond int range")
+	}
+}
+
+func TestFromJSONSchema_MultipleOf(t *testing.T) {
+	t.Parallel()
+
+	// Integers are multiples of the least common multiple of 1 and multipleOf.
+	cases := []struct {
+		schema string
+		step   int
+	}{
+		{`{"type": "integer", "multipleOf": 0.5, "minimum": 1, "maximum": 3}`, 1},
+		{`{"type": "integer", "multipleOf": 0.1, "minimum": 1, "maximum": 3}`, 1},
+		{`{"type": "integer", "multipleOf": 2.5, "minimum": 1, "maximum": 20}`, 5},
+	}
+	c := NewContextWithSeed(42)
+	for _, x := range cases {
+		g, err := FromJSONSchema([]byte(x.schema))
+		if err != nil {
+			t.Errorf("unexpected error for schema %s: %v", x.schema, err)
+			continue
+		}
+		for i := 0; i < 100; i++ {
+			if n := g(c).(int); n%x.step != 0 {
+				t.Fatalf("schema %s generated %d", x.schema, n)
+			}
+		}
+	}
+
+	// A range wider than the largest float64 doesn't overflow.
+	g, err := FromJSONSchema([]byte(`{"type": "number", "minimum": -1e308, "maximum": 1e308}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		if x := g(c).(float64); math.IsInf(x, 0) || x < -1e308 || x > 1e308 {
+			t.Fatalf("generated out of range value %v", x)
+		}
+	}
+}
+
+func TestFromJSONSchema_Errors(t *testing.T) {
+	t.Parallel()
+
+	cases := []string{
+		`{`,
+		`false`,
+		`{"$ref": "http://example.com/schema.json"}`,
+		`{"$ref": "#/$defs/missing"}`,
+		`{"type": "integer", "minimum": 5, "maximum": 4}`,
+		`{"type": "integer", "multipleOf": 10, "minimum": 1, "maximum": 9}`,
+		`{"type": "integer", "multipleOf": 0}`,
+		`{"type": "number", "exclusiveMinimum": 1, "exclusiveMaximum": 1}`,
+		`{"type": "string", "minLength": 5, "maxLength": 4}`,
+		`{"type": "string", "pattern": "("}`,
+		`{"type": "array", "items": false, "minItems": 1}`,
+		`{"enum": []}`,
+		`{"allOf": [{"type": "string"}, {"type": "integer"}]}`,
+		`{"type": "widget"}`,
+	}
+	for _, doc := range cases {
+		if _, err := FromJSONSchema([]byte(doc)); err == nil {
+			t.Errorf("expected error for schema %s", doc)
+		}
+	}
+}
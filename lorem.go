@@ -0,0 +1,70 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// loremWords is the corpus of latin words used by the text generators.
+var loremWords = []string{
+	"alias", "consequatur", "aut", "perferendis", "sit", "voluptatem",
+	"accusantium", "doloremque", "aperiam", "eaque", "ipsa", "quae", "ab",
+	"illo", "inventore", "veritatis", "et", "quasi", "architecto", "beatae",
+	"vitae", "dicta", "sunt", "explicabo", "aspernatur", "odit", "fugit",
+	"sed", "quia", "consequuntur", "magni", "dolores", "eos", "qui",
+	"ratione", "sequi", "nesciunt", "neque", "dolorem", "ipsum", "dolor",
+	"amet", "consectetur", "adipisci", "velit", "non", "numquam", "eius",
+	"modi", "tempora", "incidunt", "ut", "labore", "dolore", "magnam",
+	"aliquam", "quaerat", "enim", "ad", "minima", "veniam", "quis", "nostrum",
+	"exercitationem", "ullam", "corporis", "nemo", "ipsam", "voluptas",
+	"suscipit", "laboriosam", "nisi", "aliquid", "ex", "ea", "commodi",
+	"autem", "vel", "eum", "iure", "reprehenderit", "in", "voluptate", "esse",
+	"quam", "nihil", "molestiae", "iusto", "odio", "dignissimos", "ducimus",
+	"blanditiis", "praesentium", "laudantium", "totam", "rem", "voluptatum",
+	"deleniti", "atque", "corrupti", "quos", "quas", "molestias", "excepturi",
+	"sint", "occaecati", "cupiditate", "provident", "perspiciatis", "unde",
+	"omnis", "iste", "natus", "error", "similique", "culpa", "officia",
+	"deserunt", "mollitia", "animi", "id", "est", "laborum", "dolorum",
+	"fuga", "harum", "quidem", "rerum", "facilis", "expedita", "distinctio",
+	"nam", "libero", "tempore", "cum", "soluta", "nobis", "eligendi", "optio",
+	"cumque", "impedit", "quo", "porro", "quisquam", "minus", "quod",
+	"maxime", "placeat", "facere", "possimus", "assumenda", "repellendus",
+	"temporibus", "quibusdam", "illum", "fugiat", "nulla", "pariatur", "at",
+	"vero", "accusamus", "officiis", "debitis", "necessitatibus", "saepe",
+	"eveniet", "voluptates", "repudiandae", "recusandae", "itaque", "earum",
+	"hic", "tenetur", "a", "sapiente", "delectus", "reiciendis",
+	"voluptatibus", "maiores", "doloribus", "asperiores", "repellat",
+}
+
+// loremWord returns a word from the corpus chosen with the given PRNG.
+func loremWord(r *rand.Rand) string {
+	return loremWords[r.Intn(len(loremWords))]
+}
+
+// loremSentence returns a capitalized sentence of 4 to 15 words from the
+// corpus.  Words are occasionally followed by a comma and the sentence ends
+// in a period or, occasionally, an exclamation point.  All randomness comes
+// from the given PRNG.
+func loremSentence(r *rand.Rand) string {
+	n := 4 + r.Intn(12)
+	words := make([]string, n)
+	for i := 0; i < n; i++ {
+		words[i] = loremWord(r)
+		if i < n-1 && r.Intn(8) == 0 {
+			words[i] += ","
+		}
+	}
+	words[0] = strings.ToUpper(words[0][:1]) + words[0][1:]
+
+	end := "."
+	if r.Intn(8) == 0 {
+		end = "!"
+	}
+	return strings.Join(words, " ") + end
+}
@@ -0,0 +1,144 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// Parallel generates documents with multiple goroutines.  A Context's PRNG
+// isn't safe for concurrent use, so each document is generated with its own
+// Context, seeded from Seed and the document's index.  As a result, document
+// i is the same no matter how many workers are used or in what order
+// documents are generated.
+//
+// State stored in a Context doesn't span documents: each document starts
+// with no Pool records, no values seen by Unique generators and every Counter
+// at its start value.  Use the document index for values that must differ
+// between documents.
+type Parallel struct {
+	// Seed is the master seed from which each document's seed is derived.
+	Seed int64
+
+	// Workers is the number of goroutines to use.  If zero, GOMAXPROCS
+	// goroutines are used.
+	Workers int
+
+	// Ordered, if true, makes Generate emit documents in index order.
+	// Otherwise, documents are emitted as soon as they are generated.
+	Ordered bool
+}
+
+// Context returns the Context used for document i.  It can be used to
+// reproduce a single document without generating the others.
+func (p Parallel) Context(i int) *Context {
+	return NewContextWithSeed(deriveSeed(p.Seed, uint64(i)))
+}
+
+type parallelResult struct {
+	i   int
+	doc interface{}
+	err error
+}
+
+// Generate generates count documents and calls emit with the index and value
+// of each one.  Emit is always called from the calling goroutine, so it
+// doesn't need to be safe for concurrent use.  Only a bounded number of
+// documents are pending at a time, even when emitting in order.
+//
+// If generation fails or emit returns an error, Generate stops and returns
+// the error.
+func (p Parallel) Generate(g Generator, count int, emit func(i int, doc interface{}) error) error {
+	workers := p.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	// A token is needed to start each document and is released when the
+	// document is emitted, bounding the documents in progress or pending.
+	tokens := make(chan struct{}, 4*workers)
+	jobs := make(chan int)
+	results := make(chan parallelResult)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(jobs)
+		for i := 0; i < count; i++ {
+			select {
+			case tokens <- struct{}{}:
+			case <-done:
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				doc, err := Generate(p.Context(i), g)
+				select {
+				case results <- parallelResult{i: i, doc: doc, err: err}:
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	err := p.collect(results, emit, tokens)
+	close(done)
+	// Drain so workers blocked on sending can exit.
+	for range results {
+	}
+	return err
+}
+
+// collect emits results as they arrive, or in index order if p.Ordered.
+func (p Parallel) collect(results <-chan parallelResult, emit func(int, interface{}) error, tokens <-chan struct{}) error {
+	pending := make(map[int]interface{})
+	next := 0
+	for r := range results {
+		if r.err != nil {
+			return fmt.Errorf("document %d: %v", r.i, r.err)
+		}
+		if !p.Ordered {
+			if err := emit(r.i, r.doc); err != nil {
+				return err
+			}
+			<-tokens
+			continue
+		}
+		pending[r.i] = r.doc
+		for {
+			doc, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			if err := emit(next, doc); err != nil {
+				return err
+			}
+			<-tokens
+			next++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParallel(t *testing.T) {
+	t.Parallel()
+
+	g := Object(Map{
+		"n":    Int(1, 1000000),
+		"tags": Words(Int(1, 3)),
+	})
+	const count = 200
+
+	collect := func(p Parallel) []string {
+		docs := make([]string, count)
+		var order []int
+		err := p.Generate(g, count, func(i int, doc interface{}) error {
+			if docs[i] != "" {
+				t.Errorf("document %d emitted twice", i)
+			}
+			docs[i] = doc.(Map).String()
+			order = append(order, i)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p.Ordered {
+			for i, x := range order {
+				if i != x {
+					t.Fatalf("document %d emitted out of order", x)
+				}
+			}
+		}
+		return docs
+	}
+
+	// Document i is the same for any number of workers and either order.
+	want := collect(Parallel{Seed: 42, Workers: 1, Ordered: true})
+	for _, p := range []Parallel{
+		{Seed: 42, Workers: 4, Ordered: true},
+		{Seed: 42, Workers: 7},
+		{Seed: 42},
+	} {
+		got := collect(p)
+		for i := range want {
+			checkStringIs(t, got[i], want[i], fmt.Sprintf("%+v document %d", p, i))
+		}
+	}
+
+	// Documents can be reproduced individually.
+	p := Parallel{Seed: 42}
+	checkStringIs(t, g(p.Context(123)).(Map).String(), want[123], "reproduced document")
+
+	// A different seed gives different documents.
+	other := collect(Parallel{Seed: 43, Ordered: true})
+	if other[0] == want[0] && other[1] == want[1] {
+		t.Errorf("different seeds gave the same documents")
+	}
+}
+
+func TestParallel_Errors(t *testing.T) {
+	t.Parallel()
+
+	// Generation errors stop generation.
+	bad := Object(Map{"x": Array(Pick(1, "x"), 1)})
+	err := Parallel{Workers: 3}.Generate(bad, 1000, func(int, interface{}) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "length must be") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Emit errors stop generation.
+	stop := errors.New("stop")
+	n := 0
+	err = Parallel{Workers: 3, Ordered: true}.Generate(Int(1, 2), 1000, func(int, interface{}) error {
+		n++
+		if n == 10 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || n != 10 {
+		t.Errorf("unexpected error %v after %d documents", err, n)
+	}
+}
+
+func TestParallel_ContextState(t *testing.T) {
+	t.Parallel()
+
+	// Each document has its own Context, so Counters restart per document.
+	g := Object(Map{"id": Counter(1, 1)})
+	err := Parallel{Seed: 1, Workers: 2}.Generate(g, 3, func(i int, doc interface{}) error {
+		checkStringIs(t, doc.(Map).String(), `{"id":1}`, fmt.Sprintf("document %d", i))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// A Pool records values generated with a Context so that other generators
+// can refer to them later, such as orders referring to the IDs of generated
+// users:
+//
+//     users := jfdi.NewPool("users")
+//     user := users.Record(jfdi.Object(jfdi.Map{
+//         "id":   jfdi.UUIDv4(),
+//         "name": jfdi.Word(),
+//     }))
+//     order := jfdi.Object(jfdi.Map{
+//         "user_id": users.Pick("id"),
+//         "total":   jfdi.Float64(1, 100),
+//     })
+//
+//     c := jfdi.NewContext()
+//     for i := 0; i < 10; i++ {
+//         emitUser(user(c))
+//     }
+//     for i := 0; i < 100; i++ {
+//         emitOrder(order(c))
+//     }
+//
+// Recorded values are stored in the Context, not the Pool, so separate
+// Contexts have separate records and a Pool may be used with many Contexts.
+// Contexts forked from a Context share its records.  Parallel generates each
+// document with its own Context, so values recorded while generating one
+// document can't be picked while generating another.
+type Pool struct {
+	name string
+}
+
+// poolState holds the values recorded in a Pool for a Context and the
+// progress of any generators that pick each value once.
+type poolState struct {
+	records []interface{}
+	each    map[*eachKey]*eachState
+	weights map[*weightKey]*weightState
+}
+
+// An eachKey identifies a generator returned by Pool.Each.
+type eachKey struct {
+	field string
+}
+
+// eachState tracks the records not yet produced by a Pool.Each generator.
+// Records added since the last call are at indexes seen and above.
+type eachState struct {
+	seen      int
+	remaining []int
+}
+
+// A weightKey identifies a generator returned by Pool.WeightedPick.
+type weightKey struct {
+	_ byte
+}
+
+// weightState holds the cumulative weights of the records weighed so far by a
+// Pool.WeightedPick generator.
+type weightState struct {
+	cumulative []float64
+}
+
+// NewPool returns a new Pool.  The name is used in error messages.
+func NewPool(name string) *Pool {
+	return &Pool{name: name}
+}
+
+// Name returns the name of the Pool.
+func (p *Pool) Name() string {
+	return p.name
+}
+
+// state returns the Pool's records for a Context, creating them if needed.
+func (p *Pool) state(c *Context) *poolState {
+	s := c.state()
+	if s.pools == nil {
+		s.pools = make(map[*Pool]*poolState)
+	}
+	ps, ok := s.pools[p]
+	if !ok {
+		ps = &poolState{
+			each:    make(map[*eachKey]*eachState),
+			weights: make(map[*weightKey]*weightState),
+		}
+		s.pools[p] = ps
+	}
+	return ps
+}
+
+// Values returns the values recorded in the Pool with a Context, in the order
+// they were recorded.
+func (p *Pool) Values(c *Context) []interface{} {
+	if c == nil {
+		return nil
+	}
+	records := p.state(c).records
+	return append([]interface{}(nil), records...)
+}
+
+// Record returns a generator that produces a value from a model and records
+// it in the Pool.
+func (p *Pool) Record(model interface{}) Generator {
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		v := expand(c, model)
+		ps := p.state(c)
+		ps.records = append(ps.records, v)
+		return v
+	}
+}
+
+// Pick returns a generator that produces a field of a value chosen uniformly
+// from those recorded in the Pool.  Recorded values must be Maps with the
+// field; if the field is empty, the whole recorded value is produced.  If no
+// values have been recorded, it reports an error; see Generate.  When
+// validating, an empty Pool produces nil, and values derived from it aren't
+// checked; see Validate.
+func (p *Pool) Pick(field string) Generator {
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		records := p.state(c).records
+		if len(records) == 0 {
+			p.failEmpty(c)
+			return nil
+		}
+		return p.field(c, records[c.Rand.Intn(len(records))], field)
+	}
+}
+
+// WeightedPick works like Pick, but chooses recorded values with probability
+// proportional to the weight returned by a function of each value.  For
+// example, to favor customers with more loyalty points:
+//
+//     customers.WeightedPick("id", func(v interface{}) float64 {
+//         return float64(v.(jfdi.Map)["points"].(int) + 1)
+//     })
+//
+// The function is called once for each recorded value, the first time the
+// generator is used after the value is recorded, and the weights are kept
+// for later choices.  Weights must be finite and non-negative, and at least
+// one must be positive; otherwise, it reports an error.
+func (p *Pool) WeightedPick(field string, weight func(v interface{}) float64) Generator {
+	key := &weightKey{}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		ps := p.state(c)
+		if len(ps.records) == 0 {
+			p.failEmpty(c)
+			return nil
+		}
+		ws, ok := ps.weights[key]
+		if !ok {
+			ws = &weightState{}
+			ps.weights[key] = ws
+		}
+		// Weigh only the values recorded since the last call.
+		for i := len(ws.cumulative); i < len(ps.records); i++ {
+			w := weight(ps.records[i])
+			if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+				c.report(&GenerateError{
+					Path: c.Path(),
+					Msg:  fmt.Sprintf("weight %v for a value in pool %s is not finite and non-negative", w, p.name),
+				})
+				return nil
+			}
+			if i > 0 {
+				w += ws.cumulative[i-1]
+			}
+			ws.cumulative = append(ws.cumulative, w)
+		}
+		cumulative := ws.cumulative
+		total := cumulative[len(cumulative)-1]
+		if total == 0 || math.IsInf(total, 0) {
+			c.report(&GenerateError{
+				Path: c.Path(),
+				Msg:  fmt.Sprintf("weights for pool %s have sum %v; it must be finite and positive", p.name, total),
+			})
+			return nil
+		}
+		x := c.Rand.Float64() * total
+		i := sort.Search(len(cumulative), func(i int) bool { return cumulative[i] > x })
+		if i == len(cumulative) {
+			// Rounding may leave x at the total; use the last positive weight.
+			i = sort.Search(len(cumulative), func(i int) bool { return cumulative[i] >= total })
+		}
+		return p.field(c, ps.records[i], field)
+	}
+}
+
+// Each works like Pick, but produces each recorded value exactly once, in a
+// random order, such as for giving every user exactly one profile.  Values
+// recorded after the generator is first used are included.  Each generator
+// returned by Each tracks its progress separately for each Context.  Once
+// every recorded value has been produced, it reports an error.
+func (p *Pool) Each(field string) Generator {
+	key := &eachKey{field: field}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		ps := p.state(c)
+		es, ok := ps.each[key]
+		if !ok {
+			es = &eachState{}
+			ps.each[key] = es
+		}
+		for ; es.seen < len(ps.records); es.seen++ {
+			es.remaining = append(es.remaining, es.seen)
+		}
+		if len(es.remaining) == 0 {
+			if len(ps.records) == 0 {
+				p.failEmpty(c)
+			} else {
+				c.report(&GenerateError{Path: c.Path(), Msg: "every value in pool " + p.name + " has been used"})
+			}
+			return nil
+		}
+		i := c.Rand.Intn(len(es.remaining))
+		n := es.remaining[i]
+		last := len(es.remaining) - 1
+		es.remaining[i] = es.remaining[last]
+		es.remaining = es.remaining[:last]
+		return p.field(c, ps.records[n], field)
+	}
+}
+
+// failEmpty reports that no values have been recorded, unless the Context is
+// a probe used by Validate.  Probe Contexts never have recorded values, so
+// the caller's nil placeholder is marked as unknown instead; see Validate.
+func (p *Pool) failEmpty(c *Context) {
+	if c.probe != nil {
+		c.probe.unknown = append(c.probe.unknown, c.Path())
+		return
+	}
+	c.report(&GenerateError{Path: c.Path(), Msg: "no values recorded in pool " + p.name})
+}
+
+// field returns a field of a recorded value, or the value if the field is
+// empty.
+func (p *Pool) field(c *Context, v interface{}, field string) interface{} {
+	if field == "" {
+		return v
+	}
+	m, ok := v.(Map)
+	if !ok {
+		c.fail("pool "+p.name+" values must be Maps to pick a field", "Map", v)
+		return nil
+	}
+	x, ok := m[field]
+	if !ok {
+		c.report(&GenerateError{Path: c.Path(), Msg: "pool " + p.name + " value has no field " + field})
+		return nil
+	}
+	return x
+}
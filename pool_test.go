@@ -0,0 +1,155 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"testing"
+)
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	users := NewPool("users")
+	checkStringIs(t, users.Name(), "users", "Name")
+	user := users.Record(Object(Map{"id": Int(1, 1000000)}))
+	order := Object(Map{"user_id": users.Pick("id")})
+
+	c := NewContextWithSeed(42)
+	ids := make(map[int]bool)
+	for i := 0; i < 10; i++ {
+		ids[user(c).(Map)["id"].(int)] = true
+	}
+	if len(users.Values(c)) != 10 {
+		t.Fatalf("expected 10 recorded values, got %d", len(users.Values(c)))
+	}
+	picked := make(map[int]bool)
+	for i := 0; i < 200; i++ {
+		id := order(c).(Map)["user_id"].(int)
+		if !ids[id] {
+			t.Fatalf("order refers to unknown user %d", id)
+		}
+		picked[id] = true
+	}
+	if len(picked) != len(ids) {
+		t.Errorf("Pick didn't cover all users: %d of %d", len(picked), len(ids))
+	}
+
+	// Picking with no field returns the whole value.
+	if _, ok := users.Pick("")(c).(Map); !ok {
+		t.Errorf("Pick with no field didn't return a Map")
+	}
+
+	// Records belong to the Context; forks share them.
+	if len(users.Values(NewContext())) != 0 {
+		t.Errorf("new Context has recorded values")
+	}
+	if len(users.Values(c.Fork("orders"))) != 10 {
+		t.Errorf("forked Context doesn't share recorded values")
+	}
+}
+
+func TestPool_WeightedPick(t *testing.T) {
+	t.Parallel()
+
+	p := NewPool("weighted")
+	c := NewContextWithSeed(42)
+	for _, w := range []int{0, 1, 3} {
+		p.Record(Map{"w": w})(c)
+	}
+	calls := 0
+	g := p.WeightedPick("w", func(v interface{}) float64 {
+		calls++
+		return float64(v.(Map)["w"].(int))
+	})
+	counts := make(map[int]int)
+	for i := 0; i < 10000; i++ {
+		counts[g(c).(int)]++
+	}
+	if counts[0] != 0 {
+		t.Errorf("zero-weight value picked %d times", counts[0])
+	}
+	if counts[3] < 7200 || counts[3] > 7800 {
+		t.Errorf("weight 3 value picked %d times out of 10000; wanted about 7500", counts[3])
+	}
+
+	// Each value is weighed once, including values recorded later.
+	p.Record(Map{"w": 1000})(c)
+	if g(c).(int) != 1000 && g(c).(int) != 1000 {
+		t.Errorf("newly recorded heavy value not picked")
+	}
+	if calls != 4 {
+		t.Errorf("weight function called %d times; wanted 4", calls)
+	}
+
+	bad := p.WeightedPick("w", func(interface{}) float64 { return -1 })
+	if _, err := Generate(c, bad); err == nil {
+		t.Errorf("expected error for negative weight")
+	}
+}
+
+func TestPool_Each(t *testing.T) {
+	t.Parallel()
+
+	p := NewPool("each")
+	record := p.Record(Object(Map{"id": Int(0, 0)}))
+	c := NewContextWithSeed(42)
+	for i := 0; i < 5; i++ {
+		p.Record(Map{"id": i})(c)
+	}
+	g := p.Each("id")
+	seen := make(map[int]bool)
+	for i := 0; i < 5; i++ {
+		seen[g(c).(int)] = true
+	}
+	if len(seen) != 5 {
+		t.Errorf("Each repeated values: %v", seen)
+	}
+	if _, err := Generate(c, g); err == nil {
+		t.Errorf("expected error for exhausted pool")
+	}
+
+	// Values recorded later are included.
+	record(c)
+	if g(c).(int) != 0 {
+		t.Errorf("Each didn't produce newly recorded value")
+	}
+
+	// Separate generators track progress separately.
+	if _, err := Generate(c, p.Each("id")); err != nil {
+		t.Errorf("unexpected error for new Each generator: %v", err)
+	}
+}
+
+func TestPool_Errors(t *testing.T) {
+	t.Parallel()
+
+	p := NewPool("things")
+	each := p.Each("")
+	cases := []struct {
+		gen    Generator
+		msg    string
+		actual string
+	}{
+		{p.Pick("id"), "no values recorded in pool things", ""},
+		{p.Each("id"), "no values recorded in pool things", ""},
+		{Sequence(p.Record(42), p.Pick("id")), "pool things values must be Maps to pick a field", "int 42"},
+		{Sequence(p.Record(Map{}), p.WeightedPick("id", func(interface{}) float64 { return 1 })), "pool things value has no field id", ""},
+		{Sequence(p.Record(1), p.WeightedPick("", func(interface{}) float64 { return -1 })), "weight -1 for a value in pool things is not finite and non-negative", ""},
+		{Sequence(p.Record(1), p.WeightedPick("", func(interface{}) float64 { return 0 })), "weights for pool things have sum 0; it must be finite and positive", ""},
+		{Sequence(p.Record(1), each, each), "every value in pool things has been used", ""},
+	}
+	for _, x := range cases {
+		_, err := Generate(NewContext(), x.gen)
+		ge, ok := err.(*GenerateError)
+		if !ok {
+			t.Errorf("expected *GenerateError, got %v", err)
+			continue
+		}
+		checkStringIs(t, ge.Msg, x.msg, "error message")
+		checkStringIs(t, ge.Actual, x.actual, "error actual")
+	}
+}
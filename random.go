@@ -6,6 +6,8 @@
 
 package jfdi
 
+import "math"
+
 // Pick returns a generator that chooses one of the arguments with uniform
 // liklihood.  If the chosen item is a Generator, the value produced by that
 // generator is returned instead.  If no arguments are provided, the generator
@@ -21,3 +23,147 @@ func Pick(xs ...interface{}) Generator {
 		return nil
 	}
 }
+
+// Coin returns a generator that chooses `a` with probability `p` and `b`
+// otherwise.  If the chosen item is a Generator, the value produced by that
+// generator is returned instead.  If `p` is not in the range [0,1], it panics.
+func Coin(p float64, a, b interface{}) Generator {
+	checkProbability(p)
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		if c.Rand.Float64() < p {
+			return expand(c, a)
+		}
+		return expand(c, b)
+	}
+}
+
+// Weighted pairs a value with a relative weight, for use with WeightedPick.
+type Weighted struct {
+	Value  interface{}
+	Weight float64
+}
+
+// WeightedPick returns a generator that chooses one of the values with
+// likelihood proportional to its weight.  As with Pick, if the chosen value is
+// a Generator, the value produced by that generator is returned instead.  If
+// no choices are provided, the generator returns nil.
+//
+// For example, to choose "101" twice as often as "103":
+//
+//     jfdi.WeightedPick(
+//         jfdi.Weighted{Value: "101", Weight: 2},
+//         jfdi.Weighted{Value: "103", Weight: 1},
+//     )
+//
+// Weights must be finite and non-negative, and at least one must be positive;
+// otherwise, WeightedPick panics.  Choices are sampled in constant time with
+// the alias method, so large lists of choices are efficient.
+func WeightedPick(choices ...Weighted) Generator {
+	if len(choices) == 0 {
+		return Pick()
+	}
+	total := 0.0
+	for _, x := range choices {
+		if x.Weight < 0 || math.IsNaN(x.Weight) || math.IsInf(x.Weight, 0) {
+			panic("weights must be finite and non-negative")
+		}
+		total += x.Weight
+	}
+	if total == 0 || math.IsInf(total, 0) {
+		panic("weights must have a finite, positive sum")
+	}
+
+	prob, alias := aliasTable(choices, total)
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		i := c.Rand.Intn(len(choices))
+		if c.Rand.Float64() >= prob[i] {
+			i = alias[i]
+		}
+		return expand(c, choices[i].Value)
+	}
+}
+
+// aliasTable builds the probability and alias tables for Vose's alias method.
+func aliasTable(choices []Weighted, total float64) ([]float64, []int) {
+	n := len(choices)
+	prob := make([]float64, n)
+	alias := make([]int, n)
+	scaled := make([]float64, n)
+	var small, large []int
+	for i, x := range choices {
+		scaled[i] = x.Weight * float64(n) / total
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small, large = small[:len(small)-1], large[:len(large)-1]
+		prob[s], alias[s] = scaled[s], l
+		scaled[l] -= 1 - scaled[s]
+		if scaled[l] < 1 {
+			small = append(small, l)
+		} else {
+			large = append(large, l)
+		}
+	}
+	// Anything left over has probability 1, up to rounding error.
+	for _, i := range append(small, large...) {
+		prob[i], alias[i] = 1, i
+	}
+	return prob, alias
+}
+
+// Optional returns a generator for use as an Object value that omits the key
+// from the output Map with probability `p`; otherwise, the key's value is
+// produced from the model, which may be a value or a Generator.  The key is
+// omitted only if the Optional's nil becomes the key's value, either directly
+// or passed through a generator like Pick, Coin or a Registry reference; a
+// generator that calls Optional and produces something else in place of the
+// nil keeps the key.  Elsewhere, such as in an Array or when called directly,
+// an omitted value is nil.  If `p` is not in the range [0,1], it panics.
+func Optional(p float64, model interface{}) Generator {
+	checkProbability(p)
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		if c.Rand.Float64() < p {
+			if c.omit != nil {
+				*c.omit = true
+			}
+			return nil
+		}
+		return expand(c, model)
+	}
+}
+
+// Nullable returns a generator that produces nil (JSON null) with probability
+// `p`; otherwise, it produces a value from the model, which may be a value or
+// a Generator.  If `p` is not in the range [0,1], it panics.
+func Nullable(p float64, model interface{}) Generator {
+	checkProbability(p)
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		if c.Rand.Float64() < p {
+			return nil
+		}
+		return expand(c, model)
+	}
+}
+
+func checkProbability(p float64) {
+	if !(p >= 0 && p <= 1) {
+		panic("probability must be in the range [0,1]")
+	}
+}
@@ -6,7 +6,11 @@
 
 package jfdi
 
-import "testing"
+import (
+	"math"
+	"strings"
+	"testing"
+)
 
 func TestPick(t *testing.T) {
 	t.Parallel()
@@ -23,3 +27,156 @@ func TestPick(t *testing.T) {
 	f = Pick(Int(1, 1), Int(2, 2), Int(3, 3))
 	checkFuncCoversIntRange(func() int { return f(nil).(int) }, []int{1, 2, 3})
 }
+
+func TestWeightedPick(t *testing.T) {
+	t.Parallel()
+
+	f := Array(1, WeightedPick())
+	checkStringIs(t, f(nil).(Slice).String(), `[null]`, "empty WeightedPick")
+
+	f = WeightedPick(Weighted{23, 0}, Weighted{42, 1})
+	for i := 0; i < 100; i++ {
+		if n := f(nil).(int); n != 42 {
+			t.Fatalf("zero-weight value chosen: %d", n)
+		}
+	}
+
+	f = WeightedPick(Weighted{Int(1, 1), 1}, Weighted{Int(2, 2), 2}, Weighted{3, 3})
+	checkFuncCoversIntRange(func() int { return f(nil).(int) }, []int{1, 2, 3})
+
+	// Frequencies are proportional to weights.
+	const draws = 60000
+	counts := make(map[int]int)
+	c := NewContextWithSeed(42)
+	for i := 0; i < draws; i++ {
+		counts[f(c).(int)]++
+	}
+	for v, w := range map[int]float64{1: 1, 2: 2, 3: 3} {
+		want := draws * w / 6
+		if got := float64(counts[v]); got < want*0.95 || got > want*1.05 {
+			t.Errorf("value %d chosen %v times; wanted about %v", v, got, want)
+		}
+	}
+
+	for _, bad := range [][]Weighted{
+		{{1, -1}},
+		{{1, 0}, {2, 0}},
+		{{1, math.NaN()}},
+		{{1, math.Inf(1)}},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for weights %v", bad)
+				}
+			}()
+			WeightedPick(bad...)
+		}()
+	}
+}
+
+func TestOptional(t *testing.T) {
+	t.Parallel()
+
+	f := Object(Map{"a": Optional(0, 1), "b": Optional(1, 2), "c": 3})
+	checkStringIs(t, f(nil).(Map).String(), `{"a":1,"c":3}`, "Optional in Object")
+
+	// Outside of an Object, omitted values are nil.
+	f = Sequence(Optional(1, 1), Array(1, Optional(1, 2)), Pick(Optional(1, 3)))
+	checkStringIs(t, f(nil).(Slice).String(), `[null,[null],null]`, "Optional in Slice")
+	v, err := Generate(nil, Optional(1, 1))
+	if v != nil || err != nil {
+		t.Errorf("Optional at top level: got %v, %v", v, err)
+	}
+	if v := Optional(1, 5)(nil); v != nil {
+		t.Errorf("Optional called directly: got %#v", v)
+	}
+
+	// An Optional nested in an Object value omits the whole key.
+	f = Object(Map{"a": Pick(Optional(1, 1)), "b": Object(Map{"c": Optional(1, 2)})})
+	checkStringIs(t, f(nil).(Map).String(), `{"b":{}}`, "nested Optional")
+
+	// Passing through Pick or Coin still omits the key, but replacing the
+	// nil keeps it.
+	wrapped := func(c *Context) interface{} {
+		if v := Optional(1, Word())(c); v != nil {
+			return v
+		}
+		return "n/a"
+	}
+	f = Object(Map{"a": Coin(1, Optional(1, 1), 2), "b": wrapped})
+	checkStringIs(t, f(nil).(Map).String(), `{"b":"n/a"}`, "wrapped Optional")
+
+	// Errors describe an omitted value as nil.
+	_, err = Generate(nil, Join(Words(1), Optional(1, " ")))
+	if err == nil || !strings.Contains(err.Error(), "got nil") {
+		t.Errorf("expected error describing nil, got %v", err)
+	}
+
+	// Keys are omitted with about the given probability.
+	f = Object(Map{"x": Optional(0.25, Int(1, 1))})
+	c := NewContextWithSeed(42)
+	missing := 0
+	for i := 0; i < 10000; i++ {
+		if _, ok := f(c).(Map)["x"]; !ok {
+			missing++
+		}
+	}
+	if missing < 2300 || missing > 2700 {
+		t.Errorf("key omitted %d times out of 10000; wanted about 2500", missing)
+	}
+}
+
+func TestNullable(t *testing.T) {
+	t.Parallel()
+
+	f := Object(Map{"a": Nullable(0, 1), "b": Nullable(1, 2)})
+	checkStringIs(t, f(nil).(Map).String(), `{"a":1,"b":null}`, "Nullable")
+
+	f = Nullable(0.5, Int(1, 1))
+	nulls := 0
+	c := NewContextWithSeed(42)
+	for i := 0; i < 10000; i++ {
+		if f(c) == nil {
+			nulls++
+		}
+	}
+	if nulls < 4700 || nulls > 5300 {
+		t.Errorf("null produced %d times out of 10000; wanted about 5000", nulls)
+	}
+
+	for _, p := range []float64{-0.1, 1.1, math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for probability %v", p)
+				}
+			}()
+			Nullable(p, 1)
+		}()
+	}
+}
+
+func TestCoin(t *testing.T) {
+	t.Parallel()
+
+	f := Array(1, Coin(1, Int(1, 1), 2))
+	checkStringIs(t, f(nil).(Slice).String(), `[1]`, "Coin(1)")
+
+	f = Array(1, Coin(0, 1, Int(2, 2)))
+	checkStringIs(t, f(nil).(Slice).String(), `[2]`, "Coin(0)")
+
+	f = Coin(0.9, 1, 2)
+	checkFuncCoversIntRange(func() int { return f(nil).(int) }, []int{1, 2})
+
+	c := NewContextWithSeed(42)
+	n := 0
+	for i := 0; i < 10000; i++ {
+		if f(c).(int) == 1 {
+			n++
+		}
+	}
+	if n < 8800 || n > 9200 {
+		t.Errorf("first choice made %d times out of 10000; wanted about 9000", n)
+	}
+}
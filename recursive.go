@@ -0,0 +1,89 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"math"
+	"sync"
+)
+
+// Lazy returns a Generator that calls a function to get the Generator to use
+// the first time it's called, rather than when the model is constructed.  This
+// lets a template refer to a Generator variable that isn't assigned yet, such
+// as the model being defined or one defined later:
+//
+//     var comment jfdi.Generator
+//     comment = jfdi.Object(jfdi.Map{
+//         "text":    jfdi.Sentence(),
+//         "replies": jfdi.Array(jfdi.Int(0, 3), jfdi.Lazy(func() jfdi.Generator { return comment })),
+//     })
+//
+// The function is called only once, even if the Generator is used from
+// several goroutines.  Recursive models need a way to stop; see Taper.
+func Lazy(f func() Generator) Generator {
+	var once sync.Once
+	var g Generator
+	return func(c *Context) interface{} {
+		once.Do(func() { g = f() })
+		return g(c)
+	}
+}
+
+// Fix returns a Generator for a recursive model.  The function is called once
+// with a Generator that refers to the result of the function itself, so the
+// model can be defined in a single expression:
+//
+//     tree := jfdi.Fix(func(self jfdi.Generator) jfdi.Generator {
+//         return jfdi.Object(jfdi.Map{
+//             "value":    jfdi.Int(1, 100),
+//             "children": jfdi.Array(jfdi.Taper(0.5, jfdi.Int(1, 3), 0), self),
+//         })
+//     })
+//
+// Recursive models need a way to stop; see Taper.
+func Fix(f func(self Generator) Generator) Generator {
+	var g Generator
+	self := func(c *Context) interface{} { return g(c) }
+	g = f(self)
+	return g
+}
+
+// Taper returns a generator that produces a value from the model with
+// probability decay**depth, where depth is the Context's current nesting
+// depth, and a value from the leaf otherwise.  Either may be a value or a
+// Generator.  With a decay between 0 and 1, deeper levels of a recursive model
+// become increasingly likely to produce leaves, so recursion bottoms out
+// naturally.  At the top level, the model is always used.
+//
+// If `decay` is not in the range [0,1], it panics.
+func Taper(decay float64, model, leaf interface{}) Generator {
+	return MaxDepthTaper(0, decay, model, leaf)
+}
+
+// MaxDepthTaper works like Taper, but it takes an initial argument indicating
+// a maximum depth at or beyond which the leaf is always used.  Setting it no
+// higher than the maxDepth of a MaxDepthObject or MaxDepthArray guarantees
+// that recursion ends with leaves rather than nils.  Note that Objects and
+// Arrays each add a level of depth.  A maxDepth of 0 means depth is
+// unlimited.
+func MaxDepthTaper(maxDepth int, decay float64, model, leaf interface{}) Generator {
+	if !(decay >= 0 && decay <= 1) {
+		panic("decay must be in the range [0,1]")
+	}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		if maxDepth > 0 && c.Depth >= maxDepth {
+			return expand(c, leaf)
+		}
+		if c.Rand.Float64() < math.Pow(decay, float64(c.Depth)) {
+			return expand(c, model)
+		}
+		return expand(c, leaf)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"testing"
+)
+
+// treeDepth returns the depth of a tree of Maps with "children" Slices.
+func treeDepth(v interface{}) int {
+	m, ok := v.(Map)
+	if !ok {
+		return 0
+	}
+	max := 0
+	for _, child := range m["children"].(Slice) {
+		if d := treeDepth(child); d > max {
+			max = d
+		}
+	}
+	return max + 1
+}
+
+// hasNil reports whether a tree of Maps with "children" Slices has nil nodes.
+func hasNil(v interface{}) bool {
+	m, ok := v.(Map)
+	if !ok {
+		return true
+	}
+	for _, child := range m["children"].(Slice) {
+		if hasNil(child) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLazy(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	var leaf Generator
+	g := Array(3, Lazy(func() Generator {
+		calls++
+		return leaf
+	}))
+	leaf = Int(7, 7)
+	checkStringIs(t, g(nil).(Slice).String(), `[7,7,7]`, "Lazy")
+	g(nil)
+	if calls != 1 {
+		t.Errorf("Lazy function called %d times", calls)
+	}
+
+	// A model can refer to itself.
+	var node Generator
+	node = Object(Map{
+		"children": Array(Taper(0.5, Int(0, 3), 0), Lazy(func() Generator { return node })),
+	})
+	for i := 0; i < 100; i++ {
+		if treeDepth(node(nil)) < 1 {
+			t.Fatalf("invalid tree")
+		}
+	}
+}
+
+func TestFix(t *testing.T) {
+	t.Parallel()
+
+	tree := Fix(func(self Generator) Generator {
+		return Object(Map{
+			"value":    Int(1, 100),
+			"children": Array(Taper(0.5, Int(1, 3), 0), self),
+		})
+	})
+
+	deepest := 0
+	c := NewContextWithSeed(42)
+	for i := 0; i < 200; i++ {
+		if d := treeDepth(tree(c)); d > deepest {
+			deepest = d
+		}
+	}
+	if deepest < 2 {
+		t.Errorf("trees never recursed")
+	}
+
+	// Seeded output is reproducible.
+	checkStringIs(t, tree(NewContextWithSeed(7)).(Map).String(), tree(NewContextWithSeed(7)).(Map).String(), "seeded tree")
+}
+
+func TestTaper(t *testing.T) {
+	t.Parallel()
+
+	// At the top level, the model is always used.
+	for i := 0; i < 100; i++ {
+		if Taper(0, 1, 2)(nil).(int) != 1 {
+			t.Fatalf("Taper used leaf at top level")
+		}
+	}
+
+	// Deeper, the model is used with probability decay**depth.
+	c := NewContextWithSeed(42)
+	c.Depth = 2
+	g := Taper(0.5, 1, 2)
+	n := 0
+	for i := 0; i < 10000; i++ {
+		if g(c).(int) == 1 {
+			n++
+		}
+	}
+	if n < 2300 || n > 2700 {
+		t.Errorf("model used %d times out of 10000; wanted about 2500", n)
+	}
+
+	// MaxDepthTaper always uses the leaf at its limit, so recursion ends
+	// before MaxDepthObject returns nil.  Objects and Arrays each add a level
+	// of depth, so the tree has objects at depths 1 and 3.
+	tree := Fix(func(self Generator) Generator {
+		return MaxDepthObject(4, Map{
+			"children": Array(MaxDepthTaper(4, 1, 2, 0), self),
+		})
+	})
+	for i := 0; i < 10; i++ {
+		v := tree(nil)
+		if hasNil(v) {
+			t.Fatalf("tree has nil nodes: %v", v)
+		}
+		if d := treeDepth(v); d != 2 {
+			t.Fatalf("tree depth %d; wanted 2", d)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for invalid decay")
+		}
+	}()
+	Taper(1.5, 1, 2)
+}
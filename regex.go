@@ -0,0 +1,181 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// defaultMaxRepeat bounds unbounded repetitions for Regex.
+const defaultMaxRepeat = 8
+
+// Regex returns a generator of random strings that match a regular expression
+// in the syntax accepted by Go's regexp package.  For example, to generate
+// SKUs like "QX48213-abc":
+//
+//     jfdi.Regex(`[A-Z]{2}\d{4,6}(-[a-z]+)?`)
+//
+// The whole generated string matches the pattern; anchors (`^`, `$`) and word
+// boundaries are accepted but ignored.  Unbounded repetitions (`*`, `+` and
+// `{n,}`) repeat at most 8 times beyond their minimum; use MaxRepeatRegex for
+// a different bound.  Character classes prefer printable ASCII characters
+// when they contain any, and `.` produces only printable ASCII.
+//
+// If the pattern is invalid or can never match, Regex panics.
+func Regex(pattern string) Generator {
+	return MaxRepeatRegex(defaultMaxRepeat, pattern)
+}
+
+// MaxRepeatRegex works like Regex, but it takes an initial argument bounding
+// the number of extra repetitions produced for `*`, `+` and `{n,}`.  If
+// `maxRepeat` is negative or the pattern is invalid, it panics.
+func MaxRepeatRegex(maxRepeat int, pattern string) Generator {
+	if maxRepeat < 0 {
+		panic("maxRepeat must be non-negative")
+	}
+	g, err := regexGenerator(pattern, maxRepeat)
+	if err != nil {
+		panic(fmt.Sprintf("invalid pattern %q: %v", pattern, err))
+	}
+	return g
+}
+
+// printableASCII is the set of characters used for `.` and, when possible,
+// for character classes.
+var printableASCII = []rune{' ', '~'}
+
+// regexGenerator returns a generator of strings matching a regular expression
+// in Go's regexp/syntax (Perl) dialect.  Unbounded repetitions (`*`, `+` and
+// `{n,}`) repeat at most maxRepeat times beyond their minimum.  Anchors and
+// word boundaries are ignored, so the whole generated string matches the
+// pattern.
+func regexGenerator(pattern string, maxRepeat int) (Generator, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRegex(re); err != nil {
+		return nil, err
+	}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		var sb strings.Builder
+		writeRegex(c.Rand, &sb, re, maxRepeat)
+		return sb.String()
+	}, nil
+}
+
+// checkRegex returns an error if the regexp contains an operator that can't
+// be used to generate a string.
+func checkRegex(re *syntax.Regexp) error {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return fmt.Errorf("pattern can never match")
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return fmt.Errorf("pattern contains an empty character class")
+		}
+	}
+	for _, sub := range re.Sub {
+		if err := checkRegex(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeRegex(r *rand.Rand, sb *strings.Builder, re *syntax.Regexp, maxRepeat int) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, x := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && r.Intn(2) == 0 {
+				x = unicode.SimpleFold(x)
+			}
+			sb.WriteRune(x)
+		}
+	case syntax.OpCharClass:
+		sb.WriteRune(pickRune(r, re.Rune))
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		sb.WriteRune(pickRune(r, printableASCII))
+	case syntax.OpCapture:
+		writeRegex(r, sb, re.Sub[0], maxRepeat)
+	case syntax.OpStar:
+		writeRepeat(r, sb, re.Sub[0], 0, maxRepeat, maxRepeat)
+	case syntax.OpPlus:
+		writeRepeat(r, sb, re.Sub[0], 1, 1+maxRepeat, maxRepeat)
+	case syntax.OpQuest:
+		writeRepeat(r, sb, re.Sub[0], 0, 1, maxRepeat)
+	case syntax.OpRepeat:
+		max := re.Max
+		if max < 0 {
+			max = re.Min + maxRepeat
+		}
+		writeRepeat(r, sb, re.Sub[0], re.Min, max, maxRepeat)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeRegex(r, sb, sub, maxRepeat)
+		}
+	case syntax.OpAlternate:
+		writeRegex(r, sb, re.Sub[r.Intn(len(re.Sub))], maxRepeat)
+	}
+	// Remaining operators (anchors, word boundaries and empty matches)
+	// produce no output.
+}
+
+func writeRepeat(r *rand.Rand, sb *strings.Builder, re *syntax.Regexp, min, max, maxRepeat int) {
+	n := min + r.Intn(max-min+1)
+	for i := 0; i < n; i++ {
+		writeRegex(r, sb, re, maxRepeat)
+	}
+}
+
+// pickRune chooses a rune from a character class given as sorted pairs of
+// inclusive ranges.  If the class includes printable ASCII characters, the
+// choice is restricted to those; otherwise it's uniform over the class.
+func pickRune(r *rand.Rand, ranges []rune) rune {
+	if ascii := intersectRanges(ranges, printableASCII); len(ascii) > 0 {
+		ranges = ascii
+	}
+	total := 0
+	for i := 0; i < len(ranges); i += 2 {
+		total += int(ranges[i+1]-ranges[i]) + 1
+	}
+	n := r.Intn(total)
+	for i := 0; i < len(ranges); i += 2 {
+		size := int(ranges[i+1]-ranges[i]) + 1
+		if n < size {
+			return ranges[i] + rune(n)
+		}
+		n -= size
+	}
+	// Not reached
+	return ranges[0]
+}
+
+// intersectRanges returns the intersection of a class with a single range.
+func intersectRanges(ranges, bounds []rune) []rune {
+	var out []rune
+	for i := 0; i < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo < bounds[0] {
+			lo = bounds[0]
+		}
+		if hi > bounds[1] {
+			hi = bounds[1]
+		}
+		if lo <= hi {
+			out = append(out, lo, hi)
+		}
+	}
+	return out
+}
@@ -0,0 +1,141 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"regexp"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRegexGenerator(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pattern   string
+		maxRepeat int
+		maxLen    int
+	}{
+		{`[a-z]+@[a-z]+\.com`, 2, 11},
+		{`(ab|c)*`, 3, 6},
+		{`\d{2,}`, 0, 2},
+		{`^x?$`, 5, 1},
+	}
+	c := NewContextWithSeed(42)
+	for _, x := range cases {
+		g, err := regexGenerator(x.pattern, x.maxRepeat)
+		if err != nil {
+			t.Errorf("regexGenerator(%q): unexpected error: %v", x.pattern, err)
+			continue
+		}
+		re := regexp.MustCompile(`^(?:` + x.pattern + `)$`)
+		for i := 0; i < 100; i++ {
+			s := g(c).(string)
+			if !re.MatchString(s) || len(s) > x.maxLen {
+				t.Errorf("regexGenerator(%q, %d) produced %q", x.pattern, x.maxRepeat, s)
+				break
+			}
+		}
+	}
+
+	for _, p := range []string{`(`, `[^\x00-\x{10FFFF}]`, `a[^\x00-\x{10FFFF}]*`} {
+		if _, err := regexGenerator(p, 1); err == nil {
+			t.Errorf("regexGenerator(%q): expected error", p)
+		}
+	}
+}
+
+func TestPickRune(t *testing.T) {
+	t.Parallel()
+
+	r := NewContextWithSeed(42).Rand
+	// Printable ASCII is preferred when the class has any.
+	mixed := []rune{0x01, 0x1f, 'a', 'c', 0x3b1, 0x3c9}
+	// Otherwise, any rune in the class may be chosen.
+	greek := []rune{0x3b1, 0x3b2}
+	for i := 0; i < 100; i++ {
+		if x := pickRune(r, mixed); x < 'a' || x > 'c' {
+			t.Fatalf("pickRune chose %q from a class with printable ASCII", x)
+		}
+		if x := pickRune(r, greek); x != 0x3b1 && x != 0x3b2 {
+			t.Fatalf("pickRune chose %q outside the class", x)
+		}
+	}
+
+	got := intersectRanges([]rune{0, 'b', 'x', 0x100}, printableASCII)
+	if string(got) != " bx~" {
+		t.Errorf("intersectRanges: got %q", got)
+	}
+}
+
+func TestRegex(t *testing.T) {
+	t.Parallel()
+
+	patterns := []string{
+		``,
+		`abc`,
+		`[A-Z]{2}\d{4,6}(-[a-z]+)?`,
+		`^\w+@\w+\.(com|org|net)$`,
+		`a*b+c?`,
+		`x{3,}`,
+		`(?i)hello`,
+		`[^a-z]{5}`,
+		`\s\S\W`,
+		`.{10}`,
+		`[\p{Greek}]+`,
+		`\bword\b`,
+		`(a|bc|def){2}`,
+		`[[:xdigit:]]{8}`,
+	}
+
+	for _, p := range patterns {
+		re := regexp.MustCompile(`^(?:` + p + `)$`)
+		g := Regex(p)
+		for i := 0; i < 100; i++ {
+			s := g(nil).(string)
+			if !re.MatchString(s) {
+				t.Errorf("Regex(%q) produced non-matching %q", p, s)
+				break
+			}
+		}
+	}
+
+	// Seeded output is reproducible.
+	g := Regex(`[A-Z]{2}\d{4,6}(-[a-z]+)?`)
+	checkStringIs(t, g(NewContextWithSeed(42)).(string), g(NewContextWithSeed(42)).(string), "seeded Regex")
+}
+
+func TestMaxRepeatRegex(t *testing.T) {
+	t.Parallel()
+
+	g := MaxRepeatRegex(0, `a*b+`)
+	for i := 0; i < 100; i++ {
+		checkStringIs(t, g(nil).(string), "b", "MaxRepeatRegex(0)")
+	}
+
+	g = MaxRepeatRegex(3, `x+`)
+	for i := 0; i < 100; i++ {
+		if n := utf8.RuneCountInString(g(nil).(string)); n < 1 || n > 4 {
+			t.Fatalf("MaxRepeatRegex(3, x+) produced %d characters", n)
+		}
+	}
+
+	for _, f := range []func(){
+		func() { Regex(`(`) },
+		func() { Regex(`[^\x00-\x{10FFFF}]`) },
+		func() { MaxRepeatRegex(-1, `a`) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
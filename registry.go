@@ -0,0 +1,140 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// maxRefNesting bounds how many references to a model may be resolving at
+// once, so that recursion that never stops reports an error rather than
+// exhausting the stack.
+const maxRefNesting = 1000
+
+// A Registry holds named models, so that sub-models like an address or an
+// amount of money can be defined once and referenced from many templates:
+//
+//     reg := jfdi.NewRegistry()
+//     reg.Register("Address", jfdi.Object(jfdi.Map{
+//         "street": jfdi.Words(2),
+//         "zip":    jfdi.Digits("#####"),
+//     }))
+//     reg.Register("User", jfdi.Object(jfdi.Map{
+//         "name":    jfdi.Word(),
+//         "address": reg.Ref("Address"),
+//     }))
+//     user := reg.Ref("User")
+//
+// References are resolved when a value is generated, so models may be
+// registered in any order, may refer to themselves (see Taper) and may be
+// replaced later.  A Registry is safe for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	models map[string]interface{}
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{models: make(map[string]interface{})}
+}
+
+// Register adds a named model, which may be a value or a Generator.  If the
+// name is empty or already registered, it panics.
+func (r *Registry) Register(name string, model interface{}) {
+	if name == "" {
+		panic("model name must not be empty")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.models[name]; ok {
+		panic("model " + name + " is already registered")
+	}
+	r.models[name] = model
+}
+
+// Replace replaces a named model and returns a function that restores the
+// previous one.  All references to the name, including those in models that
+// were already constructed, use the replacement.  This allows a test to
+// override a shared sub-model:
+//
+//     defer reg.Replace("Address", jfdi.Map{"zip": "00000"})()
+//
+// Because a Registry may be shared, tests that replace models should not run
+// in parallel with other users of the same Registry.  If the name is not
+// registered, Replace panics.
+func (r *Registry) Replace(name string, model interface{}) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, ok := r.models[name]
+	if !ok {
+		panic("model " + name + " is not registered")
+	}
+	r.models[name] = model
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.models[name] = old
+	}
+}
+
+// Lookup returns a named model and whether it is registered.
+func (r *Registry) Lookup(name string) (interface{}, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	model, ok := r.models[name]
+	return model, ok
+}
+
+// Names returns the names of the registered models in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.models))
+	for k := range r.models {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Ref returns a generator that produces a value from the named model.  The
+// name is looked up each time a value is generated.  If the name is not
+// registered, it reports an error; see Generate and Validate.
+//
+// A model may refer to itself, directly or through other models, but the
+// recursion must stop: for example, through a random choice such as Taper,
+// or a MaxDepthObject or MaxDepthArray.  If more than 1000 references to the
+// same model are being resolved at once, which includes references that form
+// a cycle without any such stop (e.g. "A" is registered as reg.Ref("B") and
+// "B" as reg.Ref("A")), it reports an error rather than exhausting the stack.
+func (r *Registry) Ref(name string) Generator {
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		model, ok := r.Lookup(name)
+		if !ok {
+			c.report(&GenerateError{Path: c.Path(), Msg: "reference to unregistered model " + strconv.Quote(name)})
+			return nil
+		}
+		nested := 0
+		for _, ref := range c.refs {
+			if ref == name {
+				nested++
+			}
+		}
+		if nested >= maxRefNesting {
+			c.report(&GenerateError{Path: c.Path(), Msg: "references to model " + strconv.Quote(name) + " nest too deeply; check for a cycle"})
+			return nil
+		}
+		c.refs = append(c.refs, name)
+		defer func() { c.refs = c.refs[:len(c.refs)-1] }()
+		return expand(c, model)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	reg := NewRegistry()
+	// Models may refer to names registered later.
+	reg.Register("User", Object(Map{
+		"name":    "Alice",
+		"address": reg.Ref("Address"),
+	}))
+	reg.Register("Address", Object(Map{"zip": Digits("#####")}))
+	reg.Register("Zero", 0)
+
+	checkStringIs(t, strings.Join(reg.Names(), ","), "Address,User,Zero", "Names")
+	if _, ok := reg.Lookup("Address"); !ok {
+		t.Errorf("Lookup didn't find Address")
+	}
+	if _, ok := reg.Lookup("Missing"); ok {
+		t.Errorf("Lookup found Missing")
+	}
+	if reg.Ref("Zero")(nil).(int) != 0 {
+		t.Errorf("Ref to a value didn't return the value")
+	}
+
+	user := reg.Ref("User")
+	zip := user(nil).(Map)["address"].(Map)["zip"].(string)
+	if len(zip) != 5 {
+		t.Errorf("unexpected zip %q", zip)
+	}
+
+	// Replacing a model affects existing references until restored.
+	restore := reg.Replace("Address", Map{"zip": "00000"})
+	checkStringIs(t, user(nil).(Map).String(), `{"address":{"zip":"00000"},"name":"Alice"}`, "replaced model")
+	restore()
+	if user(nil).(Map)["address"].(Map)["zip"] == "00000" {
+		t.Errorf("model not restored")
+	}
+
+	for _, f := range []func(){
+		func() { reg.Register("User", 1) },
+		func() { reg.Register("", 1) },
+		func() { reg.Replace("Missing", 1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestRegistry_Errors(t *testing.T) {
+	t.Parallel()
+
+	reg := NewRegistry()
+	reg.Register("A", reg.Ref("B"))
+	reg.Register("B", reg.Ref("A"))
+	reg.Register("Forever", Pick(reg.Ref("Forever")))
+	reg.Register("Nested", Object(Map{"x": reg.Ref("Nested")}))
+	reg.Register("Tree", Object(Map{
+		"children": Array(Taper(0.5, Int(0, 2), 0), reg.Ref("Tree")),
+	}))
+	reg.Register("Broken", Object(Map{"x": reg.Ref("Missing")}))
+
+	nested := `references to model "Nested" nest too deeply; check for a cycle`
+	cases := []struct {
+		name string
+		msg  string
+		path string
+	}{
+		{"A", `references to model "A" nest too deeply; check for a cycle`, ""},
+		{"Broken", `reference to unregistered model "Missing"`, "/x"},
+		{"Forever", `references to model "Forever" nest too deeply; check for a cycle`, ""},
+		{"Nested", nested, strings.Repeat("/x", maxRefNesting)},
+	}
+	c := NewContext()
+	for _, x := range cases {
+		_, err := Generate(c, reg.Ref(x.name))
+		ge, ok := err.(*GenerateError)
+		if !ok {
+			t.Errorf("%s: expected *GenerateError, got %v", x.name, err)
+			continue
+		}
+		checkStringIs(t, ge.Msg, x.msg, x.name+" error message")
+		checkStringIs(t, ge.Path, x.path, x.name+" error path")
+		checkStringIs(t, ge.Expected+ge.Actual, "", x.name+" error type mismatch")
+		if len(c.refs) != 0 {
+			t.Errorf("%s: references not restored", x.name)
+		}
+	}
+
+	// Recursion through a container is not a cycle.
+	if _, err := Generate(c, reg.Ref("Tree")); err != nil {
+		t.Errorf("unexpected error for recursive model: %v", err)
+	}
+
+	// Recursion through a random choice is not a cycle.
+	reg.Register("Picked", Pick(1, reg.Ref("Picked")))
+	reg.Register("Flipped", Coin(0.5, 1, reg.Ref("Flipped")))
+	reg.Register("Tapered", Taper(0.5, reg.Ref("Tapered"), 1))
+	for _, name := range []string{"Picked", "Flipped"} {
+		if err := Validate(reg.Ref(name)); err != nil {
+			t.Errorf("unexpected Validate error for %s: %v", name, err)
+		}
+	}
+	if err := Validate(Array(1, reg.Ref("Tapered"))); err != nil {
+		t.Errorf("unexpected Validate error for Tapered: %v", err)
+	}
+
+	// Recursion through containers that never stops is reported, not fatal.
+	verr, ok := Validate(reg.Ref("Nested")).(*ValidationError)
+	if !ok || verr.Errors[0].Msg != nested {
+		t.Errorf("expected Validate error for unbounded recursion, got %v", verr)
+	}
+
+	// Validate reports broken references.
+	if err := Validate(reg.Ref("Broken")); err == nil {
+		t.Errorf("expected Validate error for broken reference")
+	}
+}
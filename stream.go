@@ -0,0 +1,110 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Stats reports the progress of an Encoder.
+type Stats struct {
+	Documents int64
+	Bytes     int64
+}
+
+// An Encoder writes newline-delimited JSON (NDJSON) documents to an output
+// stream.  Output is buffered; only one document is held in memory at a time,
+// so an Encoder can write any number of documents with bounded memory.
+//
+// Call Flush after the last Encode; the Generate methods flush before
+// returning.
+type Encoder struct {
+	w      *bufio.Writer
+	indent string
+	stats  Stats
+}
+
+// NewEncoder returns an Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w)}
+}
+
+// SetIndent makes the Encoder indent each document with the given string,
+// one level per nesting depth.  Indented output is no longer NDJSON, but is a
+// stream of JSON documents separated by newlines.
+func (e *Encoder) SetIndent(indent string) {
+	e.indent = indent
+}
+
+// Encode writes the JSON encoding of a value, followed by a newline.
+func (e *Encoder) Encode(v interface{}) error {
+	var buf []byte
+	var err error
+	if e.indent != "" {
+		buf, err = json.MarshalIndent(v, "", e.indent)
+	} else {
+		buf, err = json.Marshal(v)
+	}
+	if err != nil {
+		return err
+	}
+	buf = append(buf, '\n')
+	n, err := e.w.Write(buf)
+	e.stats.Bytes += int64(n)
+	if err != nil {
+		return err
+	}
+	e.stats.Documents++
+	return nil
+}
+
+// Flush writes any buffered output to the underlying writer.
+func (e *Encoder) Flush() error {
+	return e.w.Flush()
+}
+
+// Stats returns the number of documents and bytes written so far.
+func (e *Encoder) Stats() Stats {
+	return e.stats
+}
+
+// Generate calls the Generator count times with the Context and writes each
+// document.  If generation or encoding fails, the returned error identifies
+// the document that failed.
+func (e *Encoder) Generate(c *Context, g Generator, count int) error {
+	start := e.stats.Documents
+	return e.GenerateUntil(c, g, func(s Stats) bool {
+		return s.Documents-start >= int64(count)
+	})
+}
+
+// GenerateUntil works like Generate, but instead of a count it writes
+// documents until the stop function, which is called with the Encoder's
+// Stats before each document, returns true.  For example, to write about
+// 1 MB of documents:
+//
+//     enc.GenerateUntil(c, g, func(s jfdi.Stats) bool { return s.Bytes >= 1<<20 })
+func (e *Encoder) GenerateUntil(c *Context, g Generator, stop func(Stats) bool) error {
+	if c == nil {
+		c = NewContext()
+	}
+	for !stop(e.stats) {
+		doc, err := Generate(c, g)
+		if err != nil {
+			e.w.Flush()
+			return fmt.Errorf("document %d: %v", e.stats.Documents, err)
+		}
+		if err := e.Encode(doc); err != nil {
+			e.w.Flush()
+			return fmt.Errorf("document %d: %v", e.stats.Documents, err)
+		}
+	}
+	return e.Flush()
+}
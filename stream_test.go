@@ -0,0 +1,86 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEncoder_Generate(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	if err := enc.Generate(nil, Object(Map{"x": Int(1, 1)}), 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkStringIs(t, buf.String(), "{\"x\":1}\n{\"x\":1}\n{\"x\":1}\n", "NDJSON output")
+
+	s := enc.Stats()
+	if s.Documents != 3 || s.Bytes != int64(buf.Len()) {
+		t.Errorf("incorrect stats: %+v", s)
+	}
+
+	// Counts are relative to the current stats.
+	if err := enc.Generate(nil, Array(1, 2), 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s := enc.Stats(); s.Documents != 5 {
+		t.Errorf("incorrect stats: %+v", s)
+	}
+	if !strings.HasSuffix(buf.String(), "[2]\n[2]\n") {
+		t.Errorf("incorrect output: %q", buf.String())
+	}
+}
+
+func TestEncoder_GenerateUntil(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	err := enc.GenerateUntil(nil, Words(3), func(s Stats) bool { return s.Bytes >= 1000 })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() < 1000 || int64(buf.Len()) != enc.Stats().Bytes {
+		t.Errorf("incorrect length %d for stats %+v", buf.Len(), enc.Stats())
+	}
+	if n := strings.Count(buf.String(), "\n"); int64(n) != enc.Stats().Documents {
+		t.Errorf("incorrect document count %d for stats %+v", n, enc.Stats())
+	}
+}
+
+func TestEncoder_Errors(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	err := enc.Generate(nil, Object(Map{"x": Array("a", 1)}), 3)
+	if err == nil || !strings.HasPrefix(err.Error(), "document 0:") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err = enc.Generate(nil, func(*Context) interface{} { return func() {} }, 1)
+	if err == nil {
+		t.Errorf("expected encoding error")
+	}
+}
+
+func TestEncoder_SetIndent(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.SetIndent("  ")
+	if err := enc.Encode(Map{"x": 1}); err != nil {
+		t.Fatal(err)
+	}
+	enc.Flush()
+	checkStringIs(t, buf.String(), "{\n  \"x\": 1\n}\n", "indented output")
+}
@@ -0,0 +1,436 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadTemplate reads a template file and compiles it with ParseTemplate.
+func LoadTemplate(filename string) (Generator, error) {
+	return loadTemplate(filename, nil)
+}
+
+// LoadTemplate reads a template file and compiles it with the Registry's
+// ParseTemplate method.
+func (r *Registry) LoadTemplate(filename string) (Generator, error) {
+	return loadTemplate(filename, r)
+}
+
+func loadTemplate(filename string, registry *Registry) (Generator, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	g, err := parseTemplate(data, registry)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return g, nil
+}
+
+// ParseTemplate compiles a declarative template, written in JSON or YAML, into
+// a Generator.  This allows models to be defined without writing Go.
+//
+// Objects in the template become Object generators, arrays become Sequence
+// generators, and other values are used as-is.  An object with a single key
+// starting with `$` is a directive that constructs a Generator:
+//
+//     {"$int": [18, 65]}                      // Int(18, 65)
+//     {"$float": [0, 1]}                      // Float64(0, 1)
+//     {"$pick": ["Alice", "Bob"]}             // Pick("Alice", "Bob")
+//     {"$array": {"length": 3, "of": ...}}    // Array(3, ...)
+//     {"$sequence": [...]}                    // Sequence(...)
+//     {"$digits": "###-##-####"}              // Digits("###-##-####")
+//     {"$hexdigits": "########"}              // HexDigits("########")
+//     {"$regex": "[A-Z]{2}\\d{4}"}             // Regex(`[A-Z]{2}\d{4}`)
+//     {"$word": null}                         // Word()
+//     {"$words": 3}                           // Words(3)
+//     {"$sentence": null}                     // Sentence()
+//     {"$sentences": 3}                       // Sentences(3)
+//     {"$join": [{"$words": 3}, " "]}         // Join(Words(3), " ")
+//     {"$literal": {"$int": "not a directive"}}
+//
+// Arguments may themselves be directives where the corresponding constructor
+// accepts a Generator, e.g. {"$array": {"length": {"$int": [1, 3]}, "of": ...}}.
+// The $literal directive returns its argument unchanged, for data containing
+// keys that start with `$`.
+//
+// For example, this YAML template is equivalent to the model in the package
+// documentation:
+//
+//     name: {$pick: [Alice, Bob, Carol]}
+//     age: {$int: [18, 65]}
+//     ssn: {$digits: "###-##-####"}
+//     friends:
+//       $array:
+//         length: {$int: [1, 3]}
+//         of: {$pick: [Dan, Eve, Frank]}
+//
+// An error is returned if the template can't be parsed or a directive is
+// invalid; the error includes a JSON Pointer to the invalid value.
+//
+// Templates compiled with ParseTemplate can't use the $ref directive; see the
+// Registry's ParseTemplate method.
+func ParseTemplate(data []byte) (Generator, error) {
+	return parseTemplate(data, nil)
+}
+
+// ParseTemplate works like the ParseTemplate function, but also allows the
+// $ref directive, which refers to a model in the Registry by name:
+//
+//     {"$ref": "Address"}                     // r.Ref("Address")
+//
+// Names are resolved when values are generated, so a template may refer to
+// models that are registered after it is parsed, including the template
+// itself once registered.
+func (r *Registry) ParseTemplate(data []byte) (Generator, error) {
+	return parseTemplate(data, r)
+}
+
+func parseTemplate(data []byte, registry *Registry) (Generator, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid template: %v", err)
+	}
+	tc := &templateCompiler{registry: registry}
+	model, err := tc.compile(doc, "")
+	if err != nil {
+		return nil, err
+	}
+	if g, ok := model.(Generator); ok {
+		return g, nil
+	}
+	return func(*Context) interface{} { return model }, nil
+}
+
+// templateError reports an error in the template at the given location.
+func templateError(loc string, format string, args ...interface{}) error {
+	return fmt.Errorf("template at %q: %s", loc, fmt.Sprintf(format, args...))
+}
+
+// A templateCompiler holds the state for compiling a template.
+type templateCompiler struct {
+	registry *Registry
+}
+
+// A templateDirective compiles the argument of a directive into a Generator.
+type templateDirective func(tc *templateCompiler, arg interface{}, loc string) (Generator, error)
+
+var templateDirectives map[string]templateDirective
+
+func init() {
+	templateDirectives = map[string]templateDirective{
+		"$int":       tmplInt,
+		"$float":     tmplFloat,
+		"$pick":      tmplPick,
+		"$array":     tmplArray,
+		"$sequence":  tmplSequence,
+		"$digits":    tmplPattern(Digits),
+		"$hexdigits": tmplPattern(HexDigits),
+		"$regex":     tmplRegex,
+		"$word":      tmplNoArg(Word),
+		"$words":     tmplCount(Words),
+		"$sentence":  tmplNoArg(Sentence),
+		"$sentences": tmplCount(Sentences),
+		"$join":      tmplJoin,
+		"$ref":       tmplRef,
+	}
+}
+
+// compile converts a decoded template value into a model: either a Generator
+// or a constant value.
+func (tc *templateCompiler) compile(v interface{}, loc string) (interface{}, error) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if len(x) == 1 {
+			for k, arg := range x {
+				if k == "$literal" {
+					return Generator(func(*Context) interface{} { return copyJSON(arg) }), nil
+				}
+				if d, ok := templateDirectives[k]; ok {
+					return d(tc, arg, loc+"/"+escapePointer(k))
+				}
+			}
+		}
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			if strings.HasPrefix(k, "$") {
+				if _, ok := templateDirectives[k]; ok || k == "$literal" {
+					return nil, templateError(loc, "directive %s must be the only key in its object", k)
+				}
+				return nil, templateError(loc, "unknown directive %s (use $literal for data keys starting with $)", k)
+			}
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		model := make(Map, len(x))
+		for _, k := range keys {
+			m, err := tc.compile(x[k], loc+"/"+escapePointer(k))
+			if err != nil {
+				return nil, err
+			}
+			model[k] = m
+		}
+		return Object(model), nil
+	case map[interface{}]interface{}:
+		return nil, templateError(loc, "object keys must be strings")
+	case []interface{}:
+		models, err := tc.compileList(x, loc)
+		if err != nil {
+			return nil, err
+		}
+		return Sequence(models...), nil
+	default:
+		return v, nil
+	}
+}
+
+func (tc *templateCompiler) compileList(xs []interface{}, loc string) ([]interface{}, error) {
+	models := make([]interface{}, len(xs))
+	for i, x := range xs {
+		m, err := tc.compile(x, loc+"/"+strconv.Itoa(i))
+		if err != nil {
+			return nil, err
+		}
+		models[i] = m
+	}
+	return models, nil
+}
+
+func escapePointer(s string) string {
+	return pointerEscaper.Replace(s)
+}
+
+// templateNumbers converts a directive argument into a list of n numbers.
+func templateNumbers(arg interface{}, n int, loc string) ([]float64, error) {
+	xs, ok := arg.([]interface{})
+	if !ok || len(xs) != n {
+		return nil, templateError(loc, "argument must be an array of %d numbers", n)
+	}
+	out := make([]float64, n)
+	for i, x := range xs {
+		switch y := x.(type) {
+		case int:
+			out[i] = float64(y)
+		case float64:
+			out[i] = y
+		default:
+			return nil, templateError(loc, "argument must be an array of %d numbers", n)
+		}
+	}
+	return out, nil
+}
+
+// templateInts converts a directive argument into a list of n ints.  Ints
+// are used as is, so the full int range is available; floats must be whole
+// numbers in the int range.
+func templateInts(arg interface{}, n int, loc string) ([]int, error) {
+	if _, err := templateNumbers(arg, n, loc); err != nil {
+		return nil, err
+	}
+	xs := arg.([]interface{})
+	out := make([]int, n)
+	for i, x := range xs {
+		switch y := x.(type) {
+		case int:
+			out[i] = y
+		case float64:
+			if y != math.Trunc(y) || y < -intRange || y >= intRange {
+				return nil, templateError(loc, "bounds must be integers")
+			}
+			out[i] = int(y)
+		}
+	}
+	return out, nil
+}
+
+func tmplInt(tc *templateCompiler, arg interface{}, loc string) (Generator, error) {
+	xs, err := templateInts(arg, 2, loc)
+	if err != nil {
+		return nil, err
+	}
+	if xs[0] > xs[1] {
+		return nil, templateError(loc, "lower bound must be <= upper bound")
+	}
+	return Int(xs[0], xs[1]), nil
+}
+
+func tmplFloat(tc *templateCompiler, arg interface{}, loc string) (Generator, error) {
+	xs, err := templateNumbers(arg, 2, loc)
+	if err != nil {
+		return nil, err
+	}
+	if xs[0] > xs[1] {
+		return nil, templateError(loc, "lower bound must be <= upper bound")
+	}
+	return Float64(xs[0], xs[1]), nil
+}
+
+func tmplPick(tc *templateCompiler, arg interface{}, loc string) (Generator, error) {
+	xs, ok := arg.([]interface{})
+	if !ok {
+		return nil, templateError(loc, "argument must be an array")
+	}
+	models, err := tc.compileList(xs, loc)
+	if err != nil {
+		return nil, err
+	}
+	return Pick(models...), nil
+}
+
+func tmplSequence(tc *templateCompiler, arg interface{}, loc string) (Generator, error) {
+	xs, ok := arg.([]interface{})
+	if !ok {
+		return nil, templateError(loc, "argument must be an array")
+	}
+	models, err := tc.compileList(xs, loc)
+	if err != nil {
+		return nil, err
+	}
+	return Sequence(models...), nil
+}
+
+func tmplArray(tc *templateCompiler, arg interface{}, loc string) (Generator, error) {
+	m, ok := arg.(map[string]interface{})
+	if !ok {
+		return nil, templateError(loc, `argument must be an object with "length" and "of" keys`)
+	}
+	for k := range m {
+		if k != "length" && k != "of" {
+			return nil, templateError(loc, "unknown key %q", k)
+		}
+	}
+	length, err := tc.length(m["length"], loc+"/length")
+	if err != nil {
+		return nil, err
+	}
+	elem, err := tc.compile(m["of"], loc+"/of")
+	if err != nil {
+		return nil, err
+	}
+	return Array(length, elem), nil
+}
+
+// length compiles a length argument, which must be a non-negative integer
+// or a directive.
+func (tc *templateCompiler) length(arg interface{}, loc string) (interface{}, error) {
+	if n, ok := arg.(int); ok {
+		if n < 0 {
+			return nil, templateError(loc, "length must be non-negative")
+		}
+		return n, nil
+	}
+	if _, ok := arg.(map[string]interface{}); ok {
+		m, err := tc.compile(arg, loc)
+		if err != nil {
+			return nil, err
+		}
+		if g, ok := m.(Generator); ok {
+			return g, nil
+		}
+	}
+	return nil, templateError(loc, "length must be a non-negative integer or a directive")
+}
+
+func tmplPattern(f func(string) Generator) templateDirective {
+	return func(tc *templateCompiler, arg interface{}, loc string) (Generator, error) {
+		s, ok := arg.(string)
+		if !ok {
+			return nil, templateError(loc, "argument must be a string")
+		}
+		return f(s), nil
+	}
+}
+
+func tmplRegex(tc *templateCompiler, arg interface{}, loc string) (Generator, error) {
+	s, ok := arg.(string)
+	if !ok {
+		return nil, templateError(loc, "argument must be a string")
+	}
+	g, err := regexGenerator(s, defaultMaxRepeat)
+	if err != nil {
+		return nil, templateError(loc, "invalid pattern: %v", err)
+	}
+	return g, nil
+}
+
+func tmplRef(tc *templateCompiler, arg interface{}, loc string) (Generator, error) {
+	name, ok := arg.(string)
+	if !ok || name == "" {
+		return nil, templateError(loc, "argument must be a model name")
+	}
+	if tc.registry == nil {
+		return nil, templateError(loc, "$ref requires a template parsed by a Registry")
+	}
+	return tc.registry.Ref(name), nil
+}
+
+func tmplNoArg(f func() Generator) templateDirective {
+	return func(tc *templateCompiler, arg interface{}, loc string) (Generator, error) {
+		if arg != nil {
+			if m, ok := arg.(map[string]interface{}); !ok || len(m) != 0 {
+				return nil, templateError(loc, "argument must be null or {}")
+			}
+		}
+		return f(), nil
+	}
+}
+
+func tmplCount(f func(interface{}) Generator) templateDirective {
+	return func(tc *templateCompiler, arg interface{}, loc string) (Generator, error) {
+		n, err := tc.length(arg, loc)
+		if err != nil {
+			return nil, err
+		}
+		return f(n), nil
+	}
+}
+
+func tmplJoin(tc *templateCompiler, arg interface{}, loc string) (Generator, error) {
+	xs, ok := arg.([]interface{})
+	if !ok || len(xs) != 2 {
+		return nil, templateError(loc, "argument must be an array of inputs and separator")
+	}
+	models, err := tc.compileList(xs, loc)
+	if err != nil {
+		return nil, err
+	}
+	return Join(templateStrings(models[0]), models[1]), nil
+}
+
+// templateStrings converts a literal list of strings in a template, which is
+// compiled to a Sequence, into a generator of []string as required by Join.
+func templateStrings(model interface{}) interface{} {
+	g, ok := model.(Generator)
+	if !ok {
+		return model
+	}
+	return func(c *Context) interface{} {
+		v := g(c)
+		s, ok := v.(Slice)
+		if !ok {
+			return v
+		}
+		out := make([]string, len(s))
+		for i, x := range s {
+			str, ok := x.(string)
+			if !ok {
+				return v
+			}
+			out[i] = str
+		}
+		return out
+	}
+}
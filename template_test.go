@@ -0,0 +1,181 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParseTemplate(t *testing.T) {
+	t.Parallel()
+
+	model := Object(Map{
+		"name":    Pick("Alice", "Bob", "Carol"),
+		"age":     Int(18, 65),
+		"ssn":     Digits("###-##-####"),
+		"friends": Array(Int(1, 3), Pick("Dan", "Eve", "Frank")),
+	})
+
+	templates := map[string]string{
+		"yaml": `
+name: {$pick: [Alice, Bob, Carol]}
+age: {$int: [18, 65]}
+ssn: {$digits: "###-##-####"}
+friends:
+  $array:
+    length: {$int: [1, 3]}
+    of: {$pick: [Dan, Eve, Frank]}
+`,
+		"json": `{
+	"name": {"$pick": ["Alice", "Bob", "Carol"]},
+	"age": {"$int": [18, 65]},
+	"ssn": {"$digits": "###-##-####"},
+	"friends": {"$array": {"length": {"$int": [1, 3]}, "of": {"$pick": ["Dan", "Eve", "Frank"]}}}
+}`,
+	}
+
+	// Templates produce the same output as the equivalent Go model.
+	for label, tmpl := range templates {
+		g, err := ParseTemplate([]byte(tmpl))
+		if err != nil {
+			t.Fatalf("%s: parse error: %v", label, err)
+		}
+		c1 := NewContextWithSeed(42)
+		c2 := NewContextWithSeed(42)
+		for i := 0; i < 10; i++ {
+			checkStringIs(t, g(c1).(Map).String(), model(c2).(Map).String(), label)
+		}
+	}
+}
+
+func TestParseTemplate_Directives(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		tmpl  string
+		match string
+	}{
+		{`42`, `^42$`},
+		{`[1, {$int: [2, 2]}]`, `^\[1,2\]$`},
+		{`{$int: [9223372036854775807, 9223372036854775807]}`, `^9223372036854775807$`},
+		{`{$int: [-9223372036854775808, 2.0]}`, `^-?\d+$`},
+		{`{$int: [1, 9223372036854775807]}`, `^\d+$`},
+		{`{$float: [0, 0]}`, `^0$`},
+		{`{$sequence: [a, {b: c}]}`, `^\["a",\{"b":"c"\}\]$`},
+		{`{$hexdigits: "####"}`, `^"[0-9a-f]{4}"$`},
+		{`{$regex: "[A-Z]{2}-\\d{3}"}`, `^"[A-Z]{2}-\d{3}"$`},
+		{`{$word: null}`, `^"\w+"$`},
+		{`{$words: 2}`, `^\["\w+","\w+"\]$`},
+		{`{$sentence: {}}`, `^"[A-Z].*[.!]"$`},
+		{`{$sentences: {$int: [2, 2]}}`, `^\["[^"]+","[^"]+"\]$`},
+		{`{$join: [{$words: 3}, "-"]}`, `^"\w+-\w+-\w+"$`},
+		{`{$join: [[a, b], {$pick: [","]}]}`, `^"a,b"$`},
+		{`{$array: {length: 2, of: {x: 1}}}`, `^\[\{"x":1\},\{"x":1\}\]$`},
+		{`{$literal: {$int: [1, 2]}}`, `^\{"\$int":\[1,2\]\}$`},
+	}
+
+	for _, x := range cases {
+		g, err := ParseTemplate([]byte(x.tmpl))
+		if err != nil {
+			t.Errorf("parse error for %s: %v", x.tmpl, err)
+			continue
+		}
+		s := jsonKey(g(nil))
+		if !regexp.MustCompile(x.match).MatchString(s) {
+			t.Errorf("%s: `%s` doesn't match `%s`", x.tmpl, s, x.match)
+		}
+	}
+}
+
+func TestParseTemplate_Errors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		tmpl string
+		loc  string
+	}{
+		{`{a: [`, ``},
+		{`{a: {$int: [1]}}`, `/a/$int`},
+		{`{a: {$int: [2, 1]}}`, `/a/$int`},
+		{`{a: {$int: [1.5, 2]}}`, `/a/$int`},
+		{`{a: {$int: [1, 1.0e19]}}`, `/a/$int`},
+		{`{a: {$float: [x, 1]}}`, `/a/$float`},
+		{`{a: [{$pick: 1}]}`, `/a/0/$pick`},
+		{`{a: {$array: {length: -1, of: 1}}}`, `/a/$array/length`},
+		{`{a: {$array: {length: x, of: 1}}}`, `/a/$array/length`},
+		{`{a: {$array: {size: 1, of: 1}}}`, `/a/$array`},
+		{`{a: {$digits: 1}}`, `/a/$digits`},
+		{`{a: {$regex: "("}}`, `/a/$regex`},
+		{`{a: {$word: 1}}`, `/a/$word`},
+		{`{a: {$join: [x]}}`, `/a/$join`},
+		{`{a: {$int: [1, 2], b: 3}}`, `/a`},
+		{`{a: {$bogus: 1}}`, `/a`},
+	}
+
+	for _, x := range cases {
+		_, err := ParseTemplate([]byte(x.tmpl))
+		if err == nil {
+			t.Errorf("expected error for %s", x.tmpl)
+			continue
+		}
+		if x.loc != "" && !strings.Contains(err.Error(), `"`+x.loc+`"`) {
+			t.Errorf("error for %s doesn't include location %q: %v", x.tmpl, x.loc, err)
+		}
+	}
+}
+
+func TestRegistry_ParseTemplate(t *testing.T) {
+	t.Parallel()
+
+	reg := NewRegistry()
+	address, err := reg.ParseTemplate([]byte(`{zip: "00000"}`))
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	reg.Register("Address", address)
+	user, err := reg.ParseTemplate([]byte(`{name: Alice, address: {$ref: Address}}`))
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	checkStringIs(t, user(nil).(Map).String(), `{"address":{"zip":"00000"},"name":"Alice"}`, "$ref")
+
+	if _, err := ParseTemplate([]byte(`{a: {$ref: Address}}`)); err == nil {
+		t.Errorf("expected error for $ref without a Registry")
+	}
+	if _, err := reg.ParseTemplate([]byte(`{a: {$ref: 1}}`)); err == nil {
+		t.Errorf("expected error for invalid $ref")
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "jfdi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "model.yaml")
+	if err := ioutil.WriteFile(filename, []byte(`{x: {$int: [1, 1]}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	g, err := LoadTemplate(filename)
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	checkStringIs(t, g(nil).(Map).String(), `{"x":1}`, "loaded template")
+
+	if _, err := LoadTemplate(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+}
@@ -8,11 +8,11 @@ package jfdi
 
 import (
 	"strings"
-
-	"github.com/icrowley/fake"
 )
 
-// Word returns a generator that produces a randomly-chosen latin word.
+// Word returns a generator that produces a randomly-chosen latin word.  Like
+// all the text generators, it draws only from the Context's PRNG, so output is
+// reproducible for a seeded Context.
 func Word() Generator {
 	f := Words(1)
 	return func(c *Context) interface{} {
@@ -31,13 +31,14 @@ func Words(n interface{}) Generator {
 		if c == nil {
 			c = NewContext()
 		}
-		length, ok := toInt(c, n)
+		length, got, ok := toInt(c, n)
 		if !ok || length < 0 {
-			panic("length must be a non-negative int or generate a non-negative int")
+			c.fail("length must be a non-negative int or generate a non-negative int", "non-negative int", got)
+			length = 0
 		}
 		output := make([]string, length)
 		for i := 0; i < length; i++ {
-			output[i] = fake.Word()
+			output[i] = loremWord(c.Rand)
 		}
 		return output
 	}
@@ -63,13 +64,14 @@ func Sentences(n interface{}) Generator {
 		if c == nil {
 			c = NewContext()
 		}
-		length, ok := toInt(c, n)
+		length, got, ok := toInt(c, n)
 		if !ok || length < 0 {
-			panic("length must be a non-negative int or generate a non-negative int")
+			c.fail("length must be a non-negative int or generate a non-negative int", "non-negative int", got)
+			length = 0
 		}
 		output := make([]string, length)
 		for i := 0; i < length; i++ {
-			output[i] = strings.Title(fake.Word()) + fake.Sentence()
+			output[i] = loremSentence(c.Rand)
 		}
 		return output
 	}
@@ -84,14 +86,14 @@ func Join(inputs interface{}, separator interface{}) Generator {
 		if c == nil {
 			c = NewContext()
 		}
-		sep, ok := toStr(c, separator)
+		sep, got, ok := toStr(c, separator)
 		if !ok {
-			panic("separator must be or generate a string")
+			c.fail("separator must be or generate a string", "string", got)
 		}
 		xs := expand(c, inputs)
 		ys, ok := xs.([]string)
 		if !ok {
-			panic("inputs must be or generate a slice of string")
+			c.fail("inputs must be or generate a slice of string", "[]string", xs)
 		}
 		return strings.Join(ys, sep)
 	}
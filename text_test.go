@@ -105,3 +105,20 @@ func TestJoin(t *testing.T) {
 		}
 	}
 }
+
+func TestTextSeeded(t *testing.T) {
+	t.Parallel()
+
+	f := Object(Map{
+		"word":      Word(),
+		"words":     Words(Int(1, 5)),
+		"sentence":  Sentence(),
+		"sentences": Join(Sentences(3), " "),
+	})
+
+	c1 := NewContextWithSeed(42)
+	c2 := NewContextWithSeed(42)
+	for i := 0; i < 10; i++ {
+		checkStringIs(t, f(c1).(Map).String(), f(c2).(Map).String(), "seeded text")
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"math"
+	"time"
+)
+
+// Special layouts for FormatTime that produce numbers instead of strings.
+const (
+	UnixSeconds = "unix"
+	UnixMillis  = "unixmilli"
+)
+
+const maxDuration = time.Duration(math.MaxInt64)
+
+// Time returns a generator of time.Time values uniformly distributed in the
+// range [start,end], in the location of `start`.  If `start` is after `end`,
+// it panics.
+func Time(start, end time.Time) Generator {
+	if start.After(end) {
+		panic("first argument must not be after second argument")
+	}
+	span := end.Sub(start)
+	if span == maxDuration {
+		// The range is too wide for nanoseconds; choose a second and then a
+		// nanosecond within it.
+		secs := uint64(end.Unix() - start.Unix())
+		return func(c *Context) interface{} {
+			if c == nil {
+				c = NewContext()
+			}
+			for {
+				t := addTime(start, int64(uint64Range(c.Rand, secs)), c.Rand.Int63n(int64(time.Second)))
+				if !t.After(end) {
+					return t
+				}
+			}
+		}
+	}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		return start.Add(time.Duration(uint64Range(c.Rand, uint64(span))))
+	}
+}
+
+// TimeWith returns a generator of time.Time values in the range [start,end]
+// distributed according to a model producing float64 fractions of the range,
+// such as Beta or Triangular.  Fractions outside [0,1] are clamped.  For
+// example, to make recent timestamps more likely than old ones:
+//
+//     jfdi.TimeWith(yearAgo, now, jfdi.Beta(5, 1))
+//
+// If `start` is after `end`, it panics.
+func TimeWith(start, end time.Time, fraction interface{}) Generator {
+	if start.After(end) {
+		panic("first argument must not be after second argument")
+	}
+	span := float64(end.Unix()-start.Unix()) + float64(end.Nanosecond()-start.Nanosecond())/1e9
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		v := expand(c, fraction)
+		f, ok := v.(float64)
+		if !ok {
+			c.fail("fraction must be or generate a float64", "float64", v)
+		}
+		f = math.Max(0, math.Min(1, f))
+		secs := math.Floor(f * span)
+		nanos := math.Floor((f*span - secs) * 1e9)
+		t := addTime(start, int64(secs), int64(nanos))
+		if t.After(end) {
+			t = end
+		}
+		return t
+	}
+}
+
+// addTime adds seconds and nanoseconds to a time without the range limit of
+// time.Duration.
+func addTime(t time.Time, secs, nanos int64) time.Time {
+	return time.Unix(t.Unix()+secs, int64(t.Nanosecond())+nanos).In(t.Location())
+}
+
+// FormatTime returns a generator that formats the time.Time produced by a
+// model using a layout.  The layout may be any layout accepted by
+// time.Time.Format, such as time.RFC3339, in which case the generator
+// produces strings.  The special layouts UnixSeconds and UnixMillis produce
+// int64 values instead.
+//
+//     jfdi.FormatTime(time.RFC3339, jfdi.Time(start, end))  // "2019-03-14T15:09:26Z"
+//     jfdi.FormatTime(jfdi.UnixMillis, jfdi.Time(start, end)) // 1552576166000
+func FormatTime(layout string, model interface{}) Generator {
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		v := expand(c, model)
+		t, ok := v.(time.Time)
+		if !ok {
+			c.fail("model must be or generate a time.Time", "time.Time", v)
+			return nil
+		}
+		switch layout {
+		case UnixSeconds:
+			return t.Unix()
+		case UnixMillis:
+			return t.Unix()*1000 + int64(t.Nanosecond())/int64(time.Millisecond)
+		default:
+			return t.Format(layout)
+		}
+	}
+}
+
+// Duration returns a generator of time.Duration values uniformly distributed
+// in the range [low,high].  If `low` is greater than `high`, it panics.
+func Duration(low, high time.Duration) Generator {
+	if low > high {
+		panic("first argument must be <= second argument")
+	}
+	span := uint64(high) - uint64(low)
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		return time.Duration(uint64(low) + uint64Range(c.Rand, span))
+	}
+}
+
+// FormatDuration returns a generator that formats the time.Duration produced
+// by a model as a string like "1h15m30s".  Unformatted durations marshal to
+// JSON as integer nanoseconds.
+func FormatDuration(model interface{}) Generator {
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		v := expand(c, model)
+		d, ok := v.(time.Duration)
+		if !ok {
+			c.fail("model must be or generate a time.Duration", "time.Duration", v)
+			return nil
+		}
+		return d.String()
+	}
+}
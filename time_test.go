@@ -0,0 +1,135 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	testStart = time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	testEnd   = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestTime(t *testing.T) {
+	t.Parallel()
+
+	checkTimeInRange := func(g Generator, start, end time.Time) {
+		t.Helper()
+		for i := 0; i < 1000; i++ {
+			tm := g(nil).(time.Time)
+			if tm.Before(start) || tm.After(end) {
+				t.Fatalf("time %v not in range [%v, %v]", tm, start, end)
+			}
+		}
+	}
+
+	checkTimeInRange(Time(testStart, testEnd), testStart, testEnd)
+	checkTimeInRange(Time(testStart, testStart), testStart, testStart)
+
+	// Ranges too wide for a time.Duration are supported.
+	early := time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	checkTimeInRange(Time(early, late), early, late)
+
+	// Seeded output is reproducible.
+	g := Time(testStart, testEnd)
+	if !g(NewContextWithSeed(42)).(time.Time).Equal(g(NewContextWithSeed(42)).(time.Time)) {
+		t.Errorf("seeded times differ")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for invalid range")
+		}
+	}()
+	Time(testEnd, testStart)
+}
+
+func TestTimeWith(t *testing.T) {
+	t.Parallel()
+
+	checkStringIs(t, FormatTime(time.RFC3339, TimeWith(testStart, testEnd, 0.0))(nil).(string),
+		"2019-01-01T00:00:00Z", "fraction 0")
+	checkStringIs(t, FormatTime(time.RFC3339, TimeWith(testStart, testEnd, 1.0))(nil).(string),
+		"2020-01-01T00:00:00Z", "fraction 1")
+	checkStringIs(t, FormatTime(time.RFC3339, TimeWith(testStart, testEnd, 2.0))(nil).(string),
+		"2020-01-01T00:00:00Z", "clamped fraction")
+
+	// Skewed toward the end of the range.
+	g := TimeWith(testStart, testEnd, Beta(5, 1))
+	mid := testStart.Add(testEnd.Sub(testStart) / 2)
+	late := 0
+	c := NewContextWithSeed(42)
+	for i := 0; i < 1000; i++ {
+		if g(c).(time.Time).After(mid) {
+			late++
+		}
+	}
+	if late < 900 {
+		t.Errorf("only %d of 1000 times in second half of range", late)
+	}
+
+	if _, err := Generate(nil, TimeWith(testStart, testEnd, "x")); err == nil {
+		t.Errorf("expected error for invalid fraction")
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	t.Parallel()
+
+	tm := time.Date(2019, 3, 14, 15, 9, 26, 535000000, time.UTC)
+	cases := []struct {
+		layout string
+		want   interface{}
+	}{
+		{time.RFC3339, "2019-03-14T15:09:26Z"},
+		{"2006-01-02", "2019-03-14"},
+		{UnixSeconds, int64(1552576166)},
+		{UnixMillis, int64(1552576166535)},
+	}
+	for _, x := range cases {
+		if got := FormatTime(x.layout, tm)(nil); got != x.want {
+			t.Errorf("FormatTime(%q): got %v; wanted %v", x.layout, got, x.want)
+		}
+	}
+
+	s := FormatTime(time.RFC3339, Time(testStart, testEnd))(nil).(string)
+	if !regexp.MustCompile(`^2019-\d\d-\d\dT\d\d:\d\d:\d\dZ$`).MatchString(s) {
+		t.Errorf("unexpected formatted time %q", s)
+	}
+
+	if _, err := Generate(nil, FormatTime(time.RFC3339, "x")); err == nil {
+		t.Errorf("expected error for invalid model")
+	}
+}
+
+func TestDuration(t *testing.T) {
+	t.Parallel()
+
+	g := Duration(time.Second, time.Minute)
+	for i := 0; i < 1000; i++ {
+		if d := g(nil).(time.Duration); d < time.Second || d > time.Minute {
+			t.Fatalf("duration %v out of range", d)
+		}
+	}
+
+	checkStringIs(t, FormatDuration(Duration(90*time.Minute, 90*time.Minute))(nil).(string), "1h30m0s", "FormatDuration")
+
+	if _, err := Generate(nil, FormatDuration(1)); err == nil {
+		t.Errorf("expected error for invalid model")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for invalid range")
+		}
+	}()
+	Duration(time.Minute, time.Second)
+}
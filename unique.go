@@ -0,0 +1,107 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// defaultMaxRetries bounds the attempts Unique makes to find an unseen value.
+const defaultMaxRetries = 100
+
+// A uniqueScope identifies the values seen by a single Unique generator.
+type uniqueScope struct {
+	_ byte
+}
+
+// Unique returns a generator that produces values from a model, retrying
+// until it produces one it hasn't produced before with the same Context.  For
+// example, to generate account numbers that never collide:
+//
+//     jfdi.Unique(jfdi.Digits("########"))
+//
+// Values seen are stored in the Context, so separate Contexts are independent
+// and Contexts forked from a Context share its values.  Maps and Slices are
+// compared by their JSON encoding.  Parallel generates each document with its
+// own Context, so values are unique within each document but may repeat
+// across documents.
+//
+// If the model produces only values already seen after 100 retries, which
+// usually means its possible values are exhausted, it reports an error; see
+// Generate.  Use MaxRetryUnique for a different limit.
+func Unique(model interface{}) Generator {
+	return MaxRetryUnique(defaultMaxRetries, "", model)
+}
+
+// UniqueIn works like Unique, but values are unique within a named scope
+// rather than per generator: all generators using the same scope name with a
+// Context share the values seen.  For example, to keep emails unique across
+// customers and employees:
+//
+//     customerEmail := jfdi.UniqueIn("email", customerEmailModel)
+//     employeeEmail := jfdi.UniqueIn("email", employeeEmailModel)
+func UniqueIn(scope string, model interface{}) Generator {
+	return MaxRetryUnique(defaultMaxRetries, scope, model)
+}
+
+// MaxRetryUnique works like UniqueIn, but it takes an initial argument
+// indicating the maximum number of retries after a value already seen.  An
+// empty scope name means values are unique per generator, like Unique.  If
+// `maxRetries` is negative, it panics.
+func MaxRetryUnique(maxRetries int, scope string, model interface{}) Generator {
+	if maxRetries < 0 {
+		panic("maxRetries must be non-negative")
+	}
+	var key interface{} = scope
+	if scope == "" {
+		key = &uniqueScope{}
+	}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		s := c.state()
+		if s.unique == nil {
+			s.unique = make(map[interface{}]map[interface{}]bool)
+		}
+		seen := s.unique[key]
+		if seen == nil {
+			seen = make(map[interface{}]bool)
+			s.unique[key] = seen
+		}
+		var v interface{}
+		for i := 0; i <= maxRetries; i++ {
+			v = expand(c, model)
+			k := uniqueKey(v)
+			if !seen[k] {
+				seen[k] = true
+				return v
+			}
+		}
+		c.report(&GenerateError{
+			Path: c.Path(),
+			Msg:  fmt.Sprintf("no unique value after %d attempts (%d values seen)", maxRetries+1, len(seen)),
+		})
+		return v
+	}
+}
+
+// uniqueKey converts a value into a map key.  Values that aren't comparable,
+// like Maps and Slices, are converted to their JSON encoding.
+func uniqueKey(v interface{}) interface{} {
+	switch x := v.(type) {
+	case Map:
+		return "Map " + x.String()
+	case Slice:
+		return "Slice " + x.String()
+	}
+	if v != nil && !reflect.TypeOf(v).Comparable() {
+		return fmt.Sprintf("%T %v", v, v)
+	}
+	return v
+}
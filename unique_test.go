@@ -0,0 +1,98 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnique(t *testing.T) {
+	t.Parallel()
+
+	g := Unique(Int(1, 100))
+	c := NewContextWithSeed(42)
+	seen := make(map[int]bool)
+	for i := 0; i < 100; i++ {
+		n := g(c).(int)
+		if seen[n] {
+			t.Fatalf("Unique repeated %d", n)
+		}
+		seen[n] = true
+	}
+
+	// The value space is exhausted.
+	_, err := Generate(c, g)
+	ge, ok := err.(*GenerateError)
+	if !ok {
+		t.Fatalf("expected *GenerateError, got %v", err)
+	}
+	if !strings.HasPrefix(ge.Msg, "no unique value after 101 attempts (100 values seen)") {
+		t.Errorf("unexpected error message: %s", ge.Msg)
+	}
+	if ge.Expected != "" || ge.Actual != "" {
+		t.Errorf("exhaustion reported as a type mismatch: %v", ge)
+	}
+
+	// Separate Contexts are independent; forks share values.
+	if _, err := Generate(NewContext(), g); err != nil {
+		t.Errorf("unexpected error with new Context: %v", err)
+	}
+	if _, err := Generate(c.Fork("x"), g); err == nil {
+		t.Errorf("expected error with forked Context")
+	}
+
+	// Separate generators are independent.
+	if _, err := Generate(c, Unique(Int(1, 100))); err != nil {
+		t.Errorf("unexpected error with new generator: %v", err)
+	}
+
+	// Maps, Slices and other uncomparable values are compared by value.
+	for _, model := range []interface{}{
+		Object(Map{"x": Int(1, 2)}),
+		Array(1, Int(1, 2)),
+		Words(Int(1, 2)),
+	} {
+		u := Unique(model)
+		c := NewContext()
+		for i := 0; i < 2; i++ {
+			if _, err := Generate(c, u); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for negative maxRetries")
+		}
+	}()
+	MaxRetryUnique(-1, "", 1)
+}
+
+func TestUniqueIn(t *testing.T) {
+	t.Parallel()
+
+	a := UniqueIn("letters", Pick("a", "b"))
+	b := UniqueIn("letters", Pick("a", "b"))
+	c := NewContext()
+	x := a(c).(string)
+	y := b(c).(string)
+	if x == y {
+		t.Errorf("generators in the same scope repeated %q", x)
+	}
+	if _, err := Generate(c, a); err == nil {
+		t.Errorf("expected error for exhausted scope")
+	}
+
+	// Without retries, a repeated value is an error.
+	g := MaxRetryUnique(0, "one", 1)
+	g(c)
+	if _, err := Generate(c, g); err == nil {
+		t.Errorf("expected error with no retries")
+	}
+}
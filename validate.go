@@ -0,0 +1,112 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validationRuns is the number of times Validate dry-runs a Generator.
+// Because templates may contain random choices, a single run might not reach
+// every branch of the model.
+const validationRuns = 10
+
+// A probe collects the failures found while Validate dry-runs a Generator.
+// Unknown holds the paths of values that can't be known until real data is
+// generated, such as values picked from a Pool; failures at or below those
+// paths are not reported.
+type probe struct {
+	errs    []*GenerateError
+	unknown []string
+}
+
+// known reports whether a path is outside every subtree of unknown values.
+func (p *probe) known(path string) bool {
+	for _, u := range p.unknown {
+		if path == u || strings.HasPrefix(path, u+"/") {
+			return false
+		}
+	}
+	return true
+}
+
+// A ValidationError reports every failure found by Validate.
+type ValidationError struct {
+	Errors []*GenerateError
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d invalid template value(s): %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Validate dry-runs a Generator several times with probe Contexts to find
+// type mismatches in its model, such as a string used as an Array length or a
+// Words generator used as a Join separator.  Rather than stopping at the first
+// failure, generation continues past each invalid value with a fallback (an
+// empty Map, a zero length, an empty string), so all failures reachable in
+// the dry runs are reported together.  Each distinct failure is reported
+// once, in the order it was found.
+//
+// Probe Contexts have no values recorded in Pools, so values picked from a
+// Pool are unknown.  Failures at or below the path where such a value is
+// picked aren't reported, since they may depend on the recorded data; for
+// example, a Pool field used as an Array length isn't checked.
+//
+// If no failures are found, Validate returns nil; otherwise it returns a
+// *ValidationError.  The probe Contexts are seeded deterministically, so
+// Validate returns the same result each time for the same Generator.
+func Validate(g Generator) error {
+	var found []*GenerateError
+	seen := make(map[string]bool)
+	for i := 0; i < validationRuns; i++ {
+		p := &probe{}
+		c := NewContextWithSeed(int64(i))
+		c.probe = p
+		if _, err := Generate(c, g); err != nil {
+			// Not every failure can be skipped; e.g. a custom Generator might
+			// panic.  Record it and try the next run.
+			p.errs = append(p.errs, err.(*GenerateError))
+		}
+		for _, e := range p.errs {
+			if !p.known(e.Path) {
+				continue
+			}
+			if key := e.Error(); !seen[key] {
+				seen[key] = true
+				found = append(found, e)
+			}
+		}
+	}
+	if len(found) > 0 {
+		return &ValidationError{Errors: found}
+	}
+	return nil
+}
+
+// Compile calls a function that builds a Generator, as with Construct, and
+// then checks the result with Validate.  It returns an error if either
+// construction or validation fails.
+//
+//     g, err := jfdi.Compile(func() jfdi.Generator {
+//         return jfdi.Object(jfdi.Map{"tags": jfdi.Array(jfdi.Int(1, 3), jfdi.Word())})
+//     })
+func Compile(build func() Generator) (Generator, error) {
+	g, err := Construct(build)
+	if err != nil {
+		return nil, err
+	}
+	if err := Validate(g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
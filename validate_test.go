@@ -0,0 +1,113 @@
+// Copyright 2019 by David A. Golden. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jfdi
+
+import (
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	good := Object(Map{
+		"name": Join(Words(2), " "),
+		"tags": Array(Int(0, 3), Word()),
+		"pair": Sequence(Int(1, 2), Sentence()),
+	})
+	if err := Validate(good); err != nil {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+
+	bad := Object(Map{
+		"a": Array("three", 1),
+		"b": Join(Words(2), Words(1)),
+		"c": Object(42),
+		"d": Array(2, Words(Pick(1, "x"))),
+		"e": func(*Context) interface{} { panic("boom") },
+	})
+	err := Validate(bad)
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+
+	want := []string{
+		`length must be a non-negative int or generate a non-negative int: expected non-negative int, got string "three" (at path "/a")`,
+		`separator must be or generate a string: expected string, got []string (at path "/b")`,
+		`arguments must be Maps or generators of Maps: expected Map, got int 42 (at path "/c")`,
+		`length must be a non-negative int or generate a non-negative int: expected non-negative int, got string "x" (at path "/d/0")`,
+		`boom (at path "/e")`,
+	}
+	got := make(map[string]bool)
+	for _, e := range verr.Errors {
+		got[e.Error()] = true
+	}
+	for _, w := range want {
+		if !got[w] {
+			t.Errorf("missing validation error: %s", w)
+		}
+	}
+	if len(verr.Errors) != len(got) {
+		t.Errorf("duplicate validation errors reported: %v", verr)
+	}
+}
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	g, err := Compile(func() Generator { return Array(Int(1, 3), Word()) })
+	if err != nil || g == nil {
+		t.Errorf("unexpected Compile error: %v", err)
+	}
+
+	if _, err = Compile(func() Generator { return Array(Int(3, 1), Word()) }); err == nil {
+		t.Errorf("Compile didn't report construction error")
+	}
+
+	if _, err = Compile(func() Generator { return Array("x", Word()) }); err == nil {
+		t.Errorf("Compile didn't report validation error")
+	}
+}
+
+func TestCompile_Pool(t *testing.T) {
+	t.Parallel()
+
+	users := NewPool("users")
+	order := Object(Map{
+		"user_id":  users.Pick("id"),
+		"weighted": users.WeightedPick("id", func(interface{}) float64 { return 1 }),
+		"each":     users.Each("id"),
+	})
+	if err := Validate(order); err != nil {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+	if _, err := Compile(func() Generator { return order }); err != nil {
+		t.Errorf("unexpected Compile error: %v", err)
+	}
+
+	// Outside of validation, an empty Pool is still an error.
+	if _, err := Generate(NewContextWithSeed(1), order); err == nil {
+		t.Errorf("Generate didn't report empty pool")
+	}
+}
+
+func TestValidate_PoolDerived(t *testing.T) {
+	t.Parallel()
+
+	// Values derived from a Pool are unknown, so they aren't checked, but the
+	// rest of the model is.
+	users := NewPool("users")
+	g := Object(Map{
+		"tags": Array(users.Pick("ntags"), 1),
+		"name": Join(users.Pick("names"), " "),
+		"bad":  Array("x", 1),
+	})
+	verr, ok := Validate(g).(*ValidationError)
+	if !ok || len(verr.Errors) != 1 || verr.Errors[0].Path != "/bad" {
+		t.Errorf("expected only the /bad error, got %v", verr)
+	}
+}
@@ -7,10 +7,17 @@
 package jfdi
 
 import (
+	"math"
+	"math/rand"
 	"strings"
 	"unicode/utf8"
 )
 
+const maxInt = int(^uint(0) >> 1)
+
+// intRange is 2^63 on 64-bit platforms, the first float64 past the int range.
+const intRange = float64(maxInt/2+1) * 2
+
 // Int returns a generator that a random integer in the range [low,high].  If
 // `low` is greater than `high`, it panics.
 func Int(low, high int) Generator {
@@ -22,6 +29,16 @@ func Int(low, high int) Generator {
 			return low
 		}
 	}
+	// If the span doesn't fit in an int, e.g. for Int(math.MinInt64,
+	// math.MaxInt64), fall back to a full-width draw.
+	if span := uint64(high) - uint64(low); span >= uint64(maxInt) {
+		return func(c *Context) interface{} {
+			if c == nil {
+				c = NewContext()
+			}
+			return int(uint64(low) + uint64Range(c.Rand, span))
+		}
+	}
 	span := high - low + 1
 	return func(c *Context) interface{} {
 		if c == nil {
@@ -31,6 +48,72 @@ func Int(low, high int) Generator {
 	}
 }
 
+// Int64 returns a generator of random int64 values in the range [low,high].
+// Any range is allowed, including the full range of int64.  If `low` is
+// greater than `high`, it panics.
+func Int64(low, high int64) Generator {
+	if low > high {
+		panic("first argument must be <= second argument")
+	}
+	span := uint64(high) - uint64(low)
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		return int64(uint64(low) + uint64Range(c.Rand, span))
+	}
+}
+
+// Uint64 returns a generator of random uint64 values in the range
+// [low,high].  Any range is allowed, including the full range of uint64.  If
+// `low` is greater than `high`, it panics.
+func Uint64(low, high uint64) Generator {
+	if low > high {
+		panic("first argument must be <= second argument")
+	}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		return low + uint64Range(c.Rand, high-low)
+	}
+}
+
+// Uint32 returns a generator of random uint32 values in the range
+// [low,high].  Any range is allowed, including the full range of uint32.  If
+// `low` is greater than `high`, it panics.
+func Uint32(low, high uint32) Generator {
+	if low > high {
+		panic("first argument must be <= second argument")
+	}
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		return low + uint32(uint64Range(c.Rand, uint64(high-low)))
+	}
+}
+
+// uint64Range returns a uniformly-distributed random uint64 in the inclusive
+// range [0,n].  Unlike rand.Int63n, n may be as large as math.MaxUint64.
+func uint64Range(r *rand.Rand, n uint64) uint64 {
+	if n == math.MaxUint64 {
+		return r.Uint64()
+	}
+	n++
+	if n&(n-1) == 0 {
+		return r.Uint64() & (n - 1)
+	}
+	// Reject draws below 2**64 mod n so the remaining range is a multiple
+	// of n and every remainder is equally likely.
+	threshold := -n % n
+	for {
+		if v := r.Uint64(); v >= threshold {
+			return v % n
+		}
+	}
+}
+
 // Int31 returns a generator that a random integer in the range [low,high].  If
 // `low` is greater than `high`, it panics.  The range must not exceed 31 bits.
 func Int31(low, high int32) Generator {
@@ -63,6 +146,17 @@ func Float64(low, high float64) Generator {
 		}
 	}
 	span := high - low
+	// If the span overflows, e.g. for Float64(-math.MaxFloat64,
+	// math.MaxFloat64), interpolate between the bounds instead.
+	if math.IsInf(span, 0) {
+		return func(c *Context) interface{} {
+			if c == nil {
+				c = NewContext()
+			}
+			u := c.Rand.Float64()
+			return low*(1-u) + high*u
+		}
+	}
 	return func(c *Context) interface{} {
 		if c == nil {
 			c = NewContext()
@@ -71,6 +165,29 @@ func Float64(low, high float64) Generator {
 	}
 }
 
+// Bool returns a generator of random bool values, with true and false
+// equally likely.
+func Bool() Generator {
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		return c.Rand.Intn(2) == 0
+	}
+}
+
+// Bernoulli returns a generator of bool values that are true with probability
+// `p`.  If `p` is not in the range [0,1], it panics.
+func Bernoulli(p float64) Generator {
+	checkProbability(p)
+	return func(c *Context) interface{} {
+		if c == nil {
+			c = NewContext()
+		}
+		return c.Rand.Float64() < p
+	}
+}
+
 var hexDigits = []rune{'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', 'a', 'b', 'c', 'd', 'e', 'f'}
 
 // Digits returns a generator that replaces `#` characters in a template string
@@ -7,6 +7,7 @@
 package jfdi
 
 import (
+	"math"
 	"regexp"
 	"testing"
 )
@@ -131,3 +132,142 @@ func TestHexDigits(t *testing.T) {
 		}
 	}
 }
+
+func TestBool(t *testing.T) {
+	t.Parallel()
+
+	f := Bool()
+	checkFuncCoversIntRange(func() int {
+		if f(nil).(bool) {
+			return 1
+		}
+		return 0
+	}, []int{0, 1})
+}
+
+func TestBernoulli(t *testing.T) {
+	t.Parallel()
+
+	if Bernoulli(0)(nil).(bool) || !Bernoulli(1)(nil).(bool) {
+		t.Errorf("Bernoulli(0) or Bernoulli(1) produced wrong value")
+	}
+
+	f := Bernoulli(0.2)
+	c := NewContextWithSeed(42)
+	n := 0
+	for i := 0; i < 10000; i++ {
+		if f(c).(bool) {
+			n++
+		}
+	}
+	if n < 1800 || n > 2200 {
+		t.Errorf("true produced %d times out of 10000; wanted about 2000", n)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for invalid probability")
+		}
+	}()
+	Bernoulli(2)
+}
+
+func TestInt_FullRange(t *testing.T) {
+	t.Parallel()
+
+	f := Int(-maxInt-1, maxInt)
+	checkFuncCoversIntRange(func() int {
+		if f(nil).(int) < 0 {
+			return -1
+		}
+		return 1
+	}, []int{-1, 1})
+
+	f = Int(-1, maxInt)
+	for i := 0; i < 100; i++ {
+		if n := f(nil).(int); n < -1 {
+			t.Fatalf("Int out of range: %d", n)
+		}
+	}
+}
+
+func TestInt64(t *testing.T) {
+	t.Parallel()
+
+	checkStringIs(t, Array(1, Int64(5, 5))(nil).(Slice).String(), `[5]`, "single value Int64")
+
+	f := Int64(-1, 1)
+	checkFuncCoversIntRange(func() int { return int(f(nil).(int64)) }, []int{-1, 0, 1})
+
+	f = Int64(math.MinInt64, math.MaxInt64)
+	checkFuncCoversIntRange(func() int {
+		if f(nil).(int64) < 0 {
+			return -1
+		}
+		return 1
+	}, []int{-1, 1})
+
+	f = Int64(math.MaxInt64-1, math.MaxInt64)
+	for i := 0; i < 100; i++ {
+		if n := f(nil).(int64); n < math.MaxInt64-1 {
+			t.Fatalf("Int64 out of range: %d", n)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for invalid range")
+		}
+	}()
+	Int64(1, 0)
+}
+
+func TestUint64(t *testing.T) {
+	t.Parallel()
+
+	f := Uint64(0, 2)
+	checkFuncCoversIntRange(func() int { return int(f(nil).(uint64)) }, []int{0, 1, 2})
+
+	f = Uint64(0, math.MaxUint64)
+	checkFuncCoversIntRange(func() int {
+		if f(nil).(uint64) > math.MaxInt64 {
+			return 1
+		}
+		return 0
+	}, []int{0, 1})
+
+	f = Uint64(math.MaxUint64-2, math.MaxUint64)
+	checkFuncCoversIntRange(func() int { return int(math.MaxUint64 - f(nil).(uint64)) }, []int{0, 1, 2})
+
+	// Output marshals without loss of precision.
+	checkStringIs(t, Array(1, Uint64(math.MaxUint64, math.MaxUint64))(nil).(Slice).String(),
+		`[18446744073709551615]`, "max Uint64")
+}
+
+func TestUint32(t *testing.T) {
+	t.Parallel()
+
+	f := Uint32(math.MaxUint32-2, math.MaxUint32)
+	checkFuncCoversIntRange(func() int { return int(math.MaxUint32 - f(nil).(uint32)) }, []int{0, 1, 2})
+
+	f = Uint32(0, math.MaxUint32)
+	checkFuncCoversIntRange(func() int {
+		if f(nil).(uint32) > math.MaxInt32 {
+			return 1
+		}
+		return 0
+	}, []int{0, 1})
+
+	// Distribution over a non-power-of-two range is uniform.
+	f = Uint32(0, 2)
+	counts := make([]int, 3)
+	c := NewContextWithSeed(42)
+	for i := 0; i < 30000; i++ {
+		counts[f(c).(uint32)]++
+	}
+	for i, n := range counts {
+		if n < 9500 || n > 10500 {
+			t.Errorf("value %d produced %d times; wanted about 10000", i, n)
+		}
+	}
+}